@@ -0,0 +1,141 @@
+package fluidsynth2
+
+/*
+#cgo pkg-config: fluidsynth
+#include <fluidsynth.h>
+#include <stdlib.h>
+#include <stdint.h>
+
+extern void *goSFLoaderOpen(char *filename);
+extern int goSFLoaderRead(void *buf, fluid_long_long_t count, void *handle);
+extern int goSFLoaderSeek(void *handle, fluid_long_long_t offset, int origin);
+extern fluid_long_long_t goSFLoaderTell(void *handle);
+extern int goSFLoaderClose(void *handle);
+extern void goSettingsForeach(void *data, char *name, int type);
+extern int goPlayerPlayback(void *data, fluid_midi_event_t *event);
+extern int goAudioCallback(void *data, int len, int nfx, float **fx, int nout, float **out);
+extern void goLogError(int level, char *message);
+extern void goSeqClientCallback(unsigned int time, fluid_event_t *event, fluid_sequencer_t *seq, void *data);
+
+static int set_go_sfloader_callbacks(fluid_sfloader_t *loader) {
+	return fluid_sfloader_set_callbacks(loader,
+		(fluid_sfloader_callback_open_t)goSFLoaderOpen,
+		(fluid_sfloader_callback_read_t)goSFLoaderRead,
+		(fluid_sfloader_callback_seek_t)goSFLoaderSeek,
+		(fluid_sfloader_callback_tell_t)goSFLoaderTell,
+		(fluid_sfloader_callback_close_t)goSFLoaderClose);
+}
+
+// go_sfload_state is the state handle of the synth loading a soundfont on this thread, so that
+// goSFLoaderOpen can find the Go loaders registered on that synth
+static __thread void *go_sfload_state;
+
+static int go_synth_sfload(fluid_synth_t *synth, void *state, const char *filename, int reset_presets) {
+	void *prev = go_sfload_state;
+	go_sfload_state = state;
+	int id = fluid_synth_sfload(synth, filename, reset_presets);
+	go_sfload_state = prev;
+	return id;
+}
+
+static int go_synth_sfreload(fluid_synth_t *synth, void *state, int id) {
+	void *prev = go_sfload_state;
+	go_sfload_state = state;
+	id = fluid_synth_sfreload(synth, id);
+	go_sfload_state = prev;
+	return id;
+}
+
+static void *get_go_sfload_state(void) {
+	return go_sfload_state;
+}
+
+static int set_go_playback_callback(fluid_player_t *player, void *data) {
+	return fluid_player_set_playback_callback(player, (handle_midi_event_func_t)goPlayerPlayback, data);
+}
+
+static fluid_audio_driver_t *new_go_audio_driver(fluid_settings_t *settings, void *data) {
+	return new_fluid_audio_driver2(settings, (fluid_audio_func_t)goAudioCallback, data);
+}
+
+static fluid_seq_id_t register_go_seq_client(fluid_sequencer_t *seq, char *name, void *data) {
+	return fluid_sequencer_register_client(seq, name, (fluid_event_callback_t)goSeqClientCallback, data);
+}
+
+// go_log_function records errors for LastError and still prints them like fluidsynth does
+static void go_log_function(int level, const char *message, void *data) {
+	goLogError(level, (char *)message);
+	fluid_default_log_function(level, message, data);
+}
+
+static void set_go_log_function(void) {
+	fluid_set_log_function(FLUID_PANIC, go_log_function, NULL);
+	fluid_set_log_function(FLUID_ERR, go_log_function, NULL);
+}
+
+static void settings_foreach(fluid_settings_t *settings, void *data) {
+	fluid_settings_foreach(settings, data, (fluid_settings_foreach_t)goSettingsForeach);
+}
+*/
+import "C"
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+// The C side can't hold Go pointers, so Go values passed to callbacks are
+// wrapped in a cgo.Handle stored in C memory. The returned pointer is what
+// gets handed to fluidsynth as the callback's user data.
+
+func newCallbackHandle(v any) unsafe.Pointer {
+	p := C.malloc(C.size_t(unsafe.Sizeof(C.uintptr_t(0))))
+	*(*C.uintptr_t)(p) = C.uintptr_t(cgo.NewHandle(v))
+	return p
+}
+
+func callbackValue(p unsafe.Pointer) any {
+	return cgo.Handle(*(*C.uintptr_t)(p)).Value()
+}
+
+func deleteCallbackHandle(p unsafe.Pointer) {
+	cgo.Handle(*(*C.uintptr_t)(p)).Delete()
+	C.free(p)
+}
+
+func setGoSFLoaderCallbacks(loader *C.fluid_sfloader_t) error {
+	return fluidStatus(C.set_go_sfloader_callbacks(loader))
+}
+
+// sfload and sfreload load a soundfont with state as the context of the Go loader callbacks
+func sfload(synth *C.fluid_synth_t, state unsafe.Pointer, filename *C.char, resetPresets C.int) C.int {
+	return C.go_synth_sfload(synth, state, filename, resetPresets)
+}
+
+func sfreload(synth *C.fluid_synth_t, state unsafe.Pointer, id C.int) C.int {
+	return C.go_synth_sfreload(synth, state, id)
+}
+
+// sfloadState returns the state handle passed to the sfload or sfreload running on this thread, if any
+func sfloadState() unsafe.Pointer {
+	return C.get_go_sfload_state()
+}
+
+func settingsForeach(settings *C.fluid_settings_t, data unsafe.Pointer) {
+	C.settings_foreach(settings, data)
+}
+
+func setGoPlaybackCallback(player *C.fluid_player_t, data unsafe.Pointer) error {
+	return fluidStatus(C.set_go_playback_callback(player, data))
+}
+
+func newGoAudioDriver(settings *C.fluid_settings_t, data unsafe.Pointer) *C.fluid_audio_driver_t {
+	return C.new_go_audio_driver(settings, data)
+}
+
+func registerGoSeqClient(seq *C.fluid_sequencer_t, name *C.char, data unsafe.Pointer) C.fluid_seq_id_t {
+	return C.register_go_seq_client(seq, name, data)
+}
+
+func setGoLogFunction() {
+	C.set_go_log_function()
+}
@@ -0,0 +1,572 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	PITCH_BEND_CENTER = 8192
+	MAX_PITCH_BEND    = 16383
+)
+
+// PitchBend sets the pitch wheel of a channel, 0-16383 with 8192 being the center
+func (s *Synth) PitchBend(channel uint8, val int) error {
+	if val < 0 || val > MAX_PITCH_BEND {
+		return fmt.Errorf("invalid pitch bend: %d", val)
+	}
+	if C.fluid_synth_pitch_bend(s.ptr, C.int(channel), C.int(val)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set pitch bend: channel=%d, value=%d", channel, val)
+	}
+	return nil
+}
+
+// GetPitchBend returns the pitch wheel value of a channel
+func (s *Synth) GetPitchBend(channel uint8) (int, error) {
+	var val C.int
+	if C.fluid_synth_get_pitch_bend(s.ptr, C.int(channel), &val) == C.FLUID_FAILED {
+		return 0, fmt.Errorf("failed to get pitch bend: channel=%d", channel)
+	}
+	return int(val), nil
+}
+
+// SetPitchWheelSens sets the pitch wheel range of a channel in semitones
+func (s *Synth) SetPitchWheelSens(channel uint8, semitones int) error {
+	if C.fluid_synth_pitch_wheel_sens(s.ptr, C.int(channel), C.int(semitones)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set pitch wheel sensitivity: channel=%d, value=%d", channel, semitones)
+	}
+	return nil
+}
+
+// GetPitchWheelSens returns the pitch wheel range of a channel in semitones
+func (s *Synth) GetPitchWheelSens(channel uint8) (int, error) {
+	var val C.int
+	if C.fluid_synth_get_pitch_wheel_sens(s.ptr, C.int(channel), &val) == C.FLUID_FAILED {
+		return 0, fmt.Errorf("failed to get pitch wheel sensitivity: channel=%d", channel)
+	}
+	return int(val), nil
+}
+
+// NoteOnFrac plays a note detuned by cents. The detune is applied by setting the channel's
+// pitch wheel before the note is started, so it is limited to the pitch wheel range
+// (GetPitchWheelSens) and affects every other note sounding on the channel as well.
+// Use a separate channel per detuned note, or key tunings, for polyphonic microtonal playing.
+func (s *Synth) NoteOnFrac(channel uint8, key uint8, cents float64, velocity uint8) error {
+	sens, err := s.GetPitchWheelSens(channel)
+	if err != nil {
+		return err
+	}
+	// a note without detune only centers the pitch wheel, which works with any range
+	bend := PITCH_BEND_CENTER
+	if cents != 0 {
+		if sens <= 0 || math.Abs(cents) > float64(sens)*100 {
+			return fmt.Errorf("detune of %f cents exceeds the pitch wheel range of %d semitones", cents, sens)
+		}
+		bend += int(math.Round(cents / (float64(sens) * 100) * PITCH_BEND_CENTER))
+	}
+	if bend > MAX_PITCH_BEND {
+		bend = MAX_PITCH_BEND
+	}
+	if bend < 0 {
+		bend = 0
+	}
+	if err := s.PitchBend(channel, bend); err != nil {
+		return err
+	}
+	return s.NoteOn(channel, key, velocity)
+}
+
+// AllNotesOff releases all notes of a channel, -1 for all channels. Notes held by the
+// sustain pedal keep sounding until it is released.
+func (s *Synth) AllNotesOff(channel int) error {
+	if C.fluid_synth_all_notes_off(s.ptr, C.int(channel)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to turn notes off: channel=%d", channel)
+	}
+	s.clearNotes(channel)
+	return nil
+}
+
+// AllSoundsOff immediately silences all voices of a channel, -1 for all channels,
+// skipping their release phase.
+func (s *Synth) AllSoundsOff(channel int) error {
+	if C.fluid_synth_all_sounds_off(s.ptr, C.int(channel)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to turn sounds off: channel=%d", channel)
+	}
+	s.clearNotes(channel)
+	return nil
+}
+
+// AllNotesOffAllChannels releases the notes of every channel
+func (s *Synth) AllNotesOffAllChannels() error {
+	return s.AllNotesOff(-1)
+}
+
+// AllSoundsOffAllChannels immediately silences every channel. This is what an emergency stop
+// button should call: unlike AllNotesOffAllChannels it doesn't wait for release phases or the
+// sustain pedal, so the output goes silent right away.
+func (s *Synth) AllSoundsOffAllChannels() error {
+	return s.AllSoundsOff(-1)
+}
+
+// ResetControllers sends "reset all controllers" (CC 121) to a channel, -1 for all channels.
+// Modulation, expression, sustain, pitch bend and the other performance controllers return to
+// their defaults, while volume, pan, effect sends, the bank and program are kept. ResetGM,
+// ResetGS and ResetXG reset those as well, ProgramReset only the programs.
+func (s *Synth) ResetControllers(channel int) error {
+	if channel == -1 {
+		return s.CCAll(CC_RESET_ALL_CTRL, 0)
+	}
+	if channel < 0 || channel > math.MaxUint8 {
+		return fmt.Errorf("invalid channel: %d", channel)
+	}
+	return s.CC(uint8(channel), CC_RESET_ALL_CTRL, 0)
+}
+
+// Panic silences the synth and clears stuck state: it turns off all sounds, resets the
+// controllers of every channel (releasing the sustain pedal) and recenters pitch bend.
+// Unlike ResetGM, ResetGS and ResetXG, the programs of the channels are left as they are.
+func (s *Synth) Panic() error {
+	var errs []error
+	if err := s.AllSoundsOff(-1); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.ResetControllers(-1); err != nil {
+		errs = append(errs, err)
+	}
+	for ch := 0; ch < s.CountMIDIChannels(); ch++ {
+		if err := s.PitchBend(uint8(ch), PITCH_BEND_CENTER); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetChannelEnabled mutes or unmutes a channel. fluidsynth has no mute, so the binding drops
+// the note-ons of muted channels sent with NoteOn and SendEvents, and releases the notes that
+// are playing when a channel gets muted. Everything else, like program and controller changes,
+// still reaches the channel. Events from a Player or MIDI driver are not affected.
+func (s *Synth) SetChannelEnabled(channel uint8, enabled bool) error {
+	s.state.mu.Lock()
+	if s.state.muted == nil {
+		s.state.muted = make(map[uint8]bool)
+	}
+	if enabled {
+		delete(s.state.muted, channel)
+	} else {
+		s.state.muted[channel] = true
+	}
+	s.state.mu.Unlock()
+	if !enabled {
+		return s.AllNotesOff(int(channel))
+	}
+	return nil
+}
+
+// ChannelEnabled returns false if a channel was muted with SetChannelEnabled
+func (s *Synth) ChannelEnabled(channel uint8) bool {
+	return !s.isMuted(channel)
+}
+
+func (s *Synth) isMuted(channel uint8) bool {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.muted[channel]
+}
+
+// ChannelPressure sends channel aftertouch, scaled by the channel's SetAftertouchScale
+func (s *Synth) ChannelPressure(channel uint8, val int) error {
+	if val < 0 || val > 127 {
+		return fmt.Errorf("invalid channel pressure: %d", val)
+	}
+	if C.fluid_synth_channel_pressure(s.ptr, C.int(channel), C.int(s.scalePressure(channel, val))) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set channel pressure: channel=%d, value=%d", channel, val)
+	}
+	return nil
+}
+
+// KeyPressure sends polyphonic aftertouch, scaled by the channel's SetAftertouchScale
+func (s *Synth) KeyPressure(channel, key uint8, val int) error {
+	if val < 0 || val > 127 {
+		return fmt.Errorf("invalid key pressure: %d", val)
+	}
+	if C.fluid_synth_key_pressure(s.ptr, C.int(channel), C.int(key), C.int(s.scalePressure(channel, val))) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set key pressure: channel=%d, key=%d, value=%d", channel, key, val)
+	}
+	return nil
+}
+
+// SetAftertouchScale scales the channel and key pressure sent to a channel, 1.0 leaving it
+// unchanged. fluidsynth has no per-channel modulator depth, so the binding scales the pressure
+// values before they reach the synth (ChannelPressure, KeyPressure and SendEvents); the effect
+// pressure has on the sound is still defined by the soundfont's own modulators.
+func (s *Synth) SetAftertouchScale(channel uint8, scale float64) error {
+	if scale < 0 || math.IsNaN(scale) || math.IsInf(scale, 0) {
+		return fmt.Errorf("invalid aftertouch scale: %f", scale)
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if scale == 1 {
+		delete(s.state.pressureScale, channel)
+		return nil
+	}
+	if s.state.pressureScale == nil {
+		s.state.pressureScale = make(map[uint8]float64)
+	}
+	s.state.pressureScale[channel] = scale
+	return nil
+}
+
+func (s *Synth) scalePressure(channel uint8, val int) int {
+	s.state.mu.Lock()
+	scale, ok := s.state.pressureScale[channel]
+	s.state.mu.Unlock()
+	if !ok {
+		return val
+	}
+	scaled := int(math.Round(float64(val) * scale))
+	if scaled > 127 {
+		scaled = 127
+	}
+	return scaled
+}
+
+// GetProgram returns the soundfont ID, bank and program selected on a channel
+func (s *Synth) GetProgram(channel uint8) (sfontID, bank, program int, err error) {
+	var csfont, cbank, cprogram C.int
+	if C.fluid_synth_get_program(s.ptr, C.int(channel), &csfont, &cbank, &cprogram) == C.FLUID_FAILED {
+		return 0, 0, 0, fmt.Errorf("failed to get program: channel=%d", channel)
+	}
+	return int(csfont), int(cbank), int(cprogram), nil
+}
+
+// ProgramSelect selects a preset of a specific soundfont on a channel
+func (s *Synth) ProgramSelect(channel uint8, sfontID, bank, program int) error {
+	if C.fluid_synth_program_select(s.ptr, C.int(channel), C.int(sfontID), C.int(bank), C.int(program)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to select program: channel=%d, sfont=%d, bank=%d, program=%d", channel, sfontID, bank, program)
+	}
+	return nil
+}
+
+// GetChannelPresetName returns the name of the preset selected on a channel
+func (s *Synth) GetChannelPresetName(channel uint8) (string, error) {
+	preset := C.fluid_synth_get_channel_preset(s.ptr, C.int(channel))
+	if preset == nil {
+		return "", fmt.Errorf("no preset selected: channel=%d", channel)
+	}
+	return C.GoString(C.fluid_preset_get_name(preset)), nil
+}
+
+// GetChannelSoundFont returns the soundfont the preset of a channel comes from
+func (s *Synth) GetChannelSoundFont(channel uint8) (*SoundFont, error) {
+	if C.fluid_synth_get_channel_preset(s.ptr, C.int(channel)) == nil {
+		return nil, fmt.Errorf("no preset selected: channel=%d", channel)
+	}
+	sfontID, _, _, err := s.GetProgram(channel)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSFontByID(sfontID)
+}
+
+// copiedControllers are the controllers copied by CopyChannelState
+var copiedControllers = []int{
+	CC_MODULATION,
+	CC_VOLUME,
+	CC_PAN,
+	CC_EXPRESSION,
+	CC_SUSTAIN,
+	CC_REVERB_SEND,
+	CC_CHORUS_SEND,
+}
+
+// CopyChannelState copies the preset, pitch bend, pitch wheel sensitivity and the modulation,
+// volume, pan, expression, sustain, reverb send and chorus send controllers of src to dst.
+// Channel pressure is not copied, as fluidsynth has no getter for it.
+func (s *Synth) CopyChannelState(src, dst uint8) error {
+	sfontID, bank, program, err := s.GetProgram(src)
+	if err != nil {
+		return err
+	}
+	if err := s.ProgramSelect(dst, sfontID, bank, program); err != nil {
+		return err
+	}
+	for _, ctrl := range copiedControllers {
+		val, err := s.GetCC(src, ctrl)
+		if err != nil {
+			return err
+		}
+		if err := s.CC(dst, ctrl, val); err != nil {
+			return err
+		}
+	}
+	bend, err := s.GetPitchBend(src)
+	if err != nil {
+		return err
+	}
+	if err := s.PitchBend(dst, bend); err != nil {
+		return err
+	}
+	sens, err := s.GetPitchWheelSens(src)
+	if err != nil {
+		return err
+	}
+	return s.SetPitchWheelSens(dst, sens)
+}
+
+// drumBank is the bank fluidsynth selects on percussion channels
+const drumBank = 128
+
+// ChannelInfo describes the state of a channel
+type ChannelInfo struct {
+	Channel    uint8
+	SFontID    int
+	Bank       int
+	Program    int
+	PresetName string
+	// IsDrum is true for percussion channels, which fluidsynth switches to bank 128
+	IsDrum bool
+}
+
+// GetChannelInfo returns the preset selection of a channel in one call
+func (s *Synth) GetChannelInfo(channel uint8) (ChannelInfo, error) {
+	sfontID, bank, program, err := s.GetProgram(channel)
+	if err != nil {
+		return ChannelInfo{}, err
+	}
+	name, err := s.GetChannelPresetName(channel)
+	if err != nil {
+		return ChannelInfo{}, err
+	}
+	return ChannelInfo{
+		Channel:    channel,
+		SFontID:    sfontID,
+		Bank:       bank,
+		Program:    program,
+		PresetName: name,
+		IsDrum:     bank == drumBank,
+	}, nil
+}
+
+// MIDI controller numbers
+const (
+	CC_BANK_SELECT    = 0
+	CC_MODULATION     = 1
+	CC_DATA_ENTRY_MSB = 6
+	CC_VOLUME         = 7
+	CC_PAN            = 10
+	CC_EXPRESSION     = 11
+	CC_SUSTAIN        = 64
+	CC_REVERB_SEND    = 91
+	CC_CHORUS_SEND    = 93
+	CC_DATA_ENTRY_LSB = 38
+	CC_NRPN_LSB       = 98
+	CC_NRPN_MSB       = 99
+	CC_RPN_LSB        = 100
+	CC_RPN_MSB        = 101
+	CC_RESET_ALL_CTRL = 121
+	MAX_MIDI_CC_VALUE = 127
+)
+
+// CC sends a control change to a channel
+func (s *Synth) CC(channel uint8, ctrl, val int) error {
+	if ctrl < 0 || ctrl > 127 || val < 0 || val > MAX_MIDI_CC_VALUE {
+		return fmt.Errorf("invalid control change: controller=%d, value=%d", ctrl, val)
+	}
+	if C.fluid_synth_cc(s.ptr, C.int(channel), C.int(ctrl), C.int(val)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to send control change: channel=%d, controller=%d, value=%d", channel, ctrl, val)
+	}
+	return nil
+}
+
+// CCAll sends a control change to every MIDI channel of the synth, one channel at a time.
+// Unlike a MIDI broadcast, which is a single message, other events may arrive in between.
+func (s *Synth) CCAll(ctrl, val int) error {
+	var errs []error
+	for ch := 0; ch < s.CountMIDIChannels(); ch++ {
+		if err := s.CC(uint8(ch), ctrl, val); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetCC returns the current value of a controller on a channel
+func (s *Synth) GetCC(channel uint8, ctrl int) (int, error) {
+	var val C.int
+	if C.fluid_synth_get_cc(s.ptr, C.int(channel), C.int(ctrl), &val) == C.FLUID_FAILED {
+		return 0, fmt.Errorf("failed to get control value: channel=%d, controller=%d", channel, ctrl)
+	}
+	return int(val), nil
+}
+
+// ccClamped sends a control change with the value clamped to 0-127
+func (s *Synth) ccClamped(channel uint8, ctrl, val int) error {
+	if val < 0 {
+		val = 0
+	}
+	if val > MAX_MIDI_CC_VALUE {
+		val = MAX_MIDI_CC_VALUE
+	}
+	return s.CC(channel, ctrl, val)
+}
+
+// SetModWheel sets the modulation wheel (CC 1), 0-127
+func (s *Synth) SetModWheel(channel uint8, val int) error {
+	return s.ccClamped(channel, CC_MODULATION, val)
+}
+
+// SetVolume sets the channel volume (CC 7), 0-127
+func (s *Synth) SetVolume(channel uint8, val int) error {
+	return s.ccClamped(channel, CC_VOLUME, val)
+}
+
+// SetPan sets the channel pan (CC 10) from -1.0 (hard left) to 1.0 (hard right).
+// The left half maps onto 0-64 and the right half onto 64-127, so 0.0 is exactly the center value 64.
+func (s *Synth) SetPan(channel uint8, pan float64) error {
+	if math.IsNaN(pan) {
+		return fmt.Errorf("invalid pan: %f", pan)
+	}
+	return s.CC(channel, CC_PAN, panToCC(pan))
+}
+
+// panToCC converts a pan position to the nearest pan controller value, clamping it to -1.0-1.0
+func panToCC(pan float64) int {
+	pan = math.Max(-1, math.Min(1, pan))
+	if pan < 0 {
+		return int(math.Round(64 + pan*64))
+	}
+	return int(math.Round(64 + pan*63))
+}
+
+// ccToPan converts a pan controller value to a pan position, the inverse of panToCC
+func ccToPan(val int) float64 {
+	if val < 64 {
+		return float64(val-64) / 64
+	}
+	return float64(val-64) / 63
+}
+
+// GetChannelVolume returns the volume controller (CC 7) of a channel as 0.0-1.0
+func (s *Synth) GetChannelVolume(channel uint8) (float64, error) {
+	val, err := s.GetCC(channel, CC_VOLUME)
+	if err != nil {
+		return 0, err
+	}
+	return float64(val) / MAX_MIDI_CC_VALUE, nil
+}
+
+// GetChannelPan returns the pan controller (CC 10) of a channel as -1.0 (left) to 1.0 (right),
+// the inverse of SetPan
+func (s *Synth) GetChannelPan(channel uint8) (float64, error) {
+	val, err := s.GetCC(channel, CC_PAN)
+	if err != nil {
+		return 0, err
+	}
+	return ccToPan(val), nil
+}
+
+// SetExpression sets the expression controller (CC 11), 0-127
+func (s *Synth) SetExpression(channel uint8, val int) error {
+	return s.ccClamped(channel, CC_EXPRESSION, val)
+}
+
+// SetSustain presses or releases the sustain pedal (CC 64)
+func (s *Synth) SetSustain(channel uint8, on bool) error {
+	if on {
+		return s.CC(channel, CC_SUSTAIN, MAX_MIDI_CC_VALUE)
+	}
+	return s.CC(channel, CC_SUSTAIN, 0)
+}
+
+// SetReverbSend sets the reverb send level (CC 91), 0-127
+func (s *Synth) SetReverbSend(channel uint8, val int) error {
+	return s.ccClamped(channel, CC_REVERB_SEND, val)
+}
+
+// SetChorusSend sets the chorus send level (CC 93), 0-127
+func (s *Synth) SetChorusSend(channel uint8, val int) error {
+	return s.ccClamped(channel, CC_CHORUS_SEND, val)
+}
+
+// SetChannelReverbSend sets the reverb send of a channel from an amount between 0.0 and 1.0,
+// which is mapped to CC 91 values 0-127
+func (s *Synth) SetChannelReverbSend(channel uint8, amount float64) error {
+	val, err := ccAmount(amount)
+	if err != nil {
+		return err
+	}
+	return s.CC(channel, CC_REVERB_SEND, val)
+}
+
+// SetChannelChorusSend sets the chorus send of a channel from an amount between 0.0 and 1.0,
+// which is mapped to CC 93 values 0-127
+func (s *Synth) SetChannelChorusSend(channel uint8, amount float64) error {
+	val, err := ccAmount(amount)
+	if err != nil {
+		return err
+	}
+	return s.CC(channel, CC_CHORUS_SEND, val)
+}
+
+// ccAmount converts an amount between 0.0 and 1.0 to the nearest controller value
+func ccAmount(amount float64) (int, error) {
+	if math.IsNaN(amount) || amount < 0 || amount > 1 {
+		return 0, fmt.Errorf("invalid amount: %f", amount)
+	}
+	return int(math.Round(amount * MAX_MIDI_CC_VALUE)), nil
+}
+
+// rpnNull deselects the parameter after a (N)RPN write, so later data entry messages don't change it
+const rpnNull = 127
+
+func (s *Synth) setParameter(channel uint8, ccMSB, ccLSB, paramMSB, paramLSB, dataMSB, dataLSB int) error {
+	for _, v := range []int{paramMSB, paramLSB, dataMSB, dataLSB} {
+		if v < 0 || v > 127 {
+			return fmt.Errorf("invalid parameter value: %d", v)
+		}
+	}
+	// fluidsynth applies the parameter when the data entry MSB arrives, so the LSB goes first
+	for _, cc := range [][2]int{
+		{ccMSB, paramMSB},
+		{ccLSB, paramLSB},
+		{CC_DATA_ENTRY_LSB, dataLSB},
+		{CC_DATA_ENTRY_MSB, dataMSB},
+		{ccMSB, rpnNull},
+		{ccLSB, rpnNull},
+	} {
+		if err := s.CC(channel, cc[0], cc[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetRPN sets a registered parameter with CC 101/100 (parameter) and 6/38 (data)
+func (s *Synth) SetRPN(channel uint8, rpnMSB, rpnLSB, dataMSB, dataLSB int) error {
+	return s.setParameter(channel, CC_RPN_MSB, CC_RPN_LSB, rpnMSB, rpnLSB, dataMSB, dataLSB)
+}
+
+// SetNRPN sets a non-registered parameter with CC 99/98 (parameter) and 6/38 (data)
+func (s *Synth) SetNRPN(channel uint8, nrpnMSB, nrpnLSB, dataMSB, dataLSB int) error {
+	return s.setParameter(channel, CC_NRPN_MSB, CC_NRPN_LSB, nrpnMSB, nrpnLSB, dataMSB, dataLSB)
+}
+
+// SetPitchBendRange sets the pitch wheel range through RPN 0. fluidsynth only uses the
+// semitones, the cents are sent for completeness.
+func (s *Synth) SetPitchBendRange(channel uint8, semitones, cents int) error {
+	return s.SetRPN(channel, 0, 0, semitones, cents)
+}
+
+// SetTranspose shifts all notes on a channel by semitones using the coarse tune generator.
+// Sounding notes are retuned immediately, no notes are retriggered.
+func (s *Synth) SetTranspose(channel uint8, semitones int) error {
+	if semitones < -120 || semitones > 120 {
+		return fmt.Errorf("invalid transpose: %d semitones", semitones)
+	}
+	return s.SetGen(channel, GEN_COARSETUNE, float64(semitones))
+}
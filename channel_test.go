@@ -0,0 +1,94 @@
+package fluidsynth2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPanToCC(t *testing.T) {
+	tests := []struct {
+		pan  float64
+		want int
+	}{
+		{-2, 0},
+		{-1, 0},
+		{-0.5, 32},
+		{-1.0 / 128, 64},
+		{0, 64},
+		{0.5, 96},
+		{1, 127},
+		{3, 127},
+		{math.Inf(-1), 0},
+	}
+	for _, tt := range tests {
+		if got := panToCC(tt.pan); got != tt.want {
+			t.Errorf("panToCC(%f) = %d, want %d", tt.pan, got, tt.want)
+		}
+	}
+}
+
+func TestCCToPanRoundTrip(t *testing.T) {
+	for val := 0; val <= MAX_MIDI_CC_VALUE; val++ {
+		pan := ccToPan(val)
+		if pan < -1 || pan > 1 {
+			t.Errorf("ccToPan(%d) = %f, outside -1.0-1.0", val, pan)
+		}
+		if got := panToCC(pan); got != val {
+			t.Errorf("panToCC(ccToPan(%d)) = %d", val, got)
+		}
+	}
+	for _, tt := range []struct {
+		val  int
+		want float64
+	}{{0, -1}, {64, 0}, {127, 1}} {
+		if got := ccToPan(tt.val); got != tt.want {
+			t.Errorf("ccToPan(%d) = %f, want %f", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestCCAmount(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   int
+		valid  bool
+	}{
+		{0, 0, true},
+		{0.5, 64, true},
+		{0.25, 32, true},
+		{1, 127, true},
+		{-0.01, 0, false},
+		{1.01, 0, false},
+		{math.NaN(), 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ccAmount(tt.amount)
+		if (err == nil) != tt.valid {
+			t.Errorf("ccAmount(%f): error %v, want valid %t", tt.amount, err, tt.valid)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ccAmount(%f) = %d, want %d", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestNoteOnFracZeroSens(t *testing.T) {
+	sf := testSoundFont(t)
+	synth := newTestSynth(t)
+	if _, err := synth.SFLoad(sf, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := synth.SetPitchWheelSens(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := synth.NoteOnFrac(0, 60, 0, 100); err != nil {
+		t.Errorf("NoteOnFrac without detune and a pitch wheel range of 0: %v", err)
+	}
+	if bend, err := synth.GetPitchBend(0); err != nil || bend != PITCH_BEND_CENTER {
+		t.Errorf("pitch bend = %d (%v), want %d", bend, err, PITCH_BEND_CENTER)
+	}
+	if err := synth.NoteOnFrac(0, 62, 10, 100); err == nil {
+		t.Error("NoteOnFrac detuned a note with a pitch wheel range of 0")
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !fluidsynth21
+
+package fluidsynth2
+
+/*
+#cgo pkg-config: fluidsynth
+#include <fluidsynth.h>
+
+extern int goPlayerTick(void *data, int tick);
+
+static int set_go_tick_callback(fluid_player_t *player, void *data) {
+	return fluid_player_set_tick_callback(player, (handle_midi_tick_func_t)goPlayerTick, data);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Player and synth functions that were added in fluidsynth 2.2. compat_legacy.go replaces them
+// for the fluidsynth21 build, see effects_legacy.go for the version matrix.
+
+func setGoTickCallback(player *C.fluid_player_t, data unsafe.Pointer) error {
+	return fluidStatus(C.set_go_tick_callback(player, data))
+}
+
+func playerDivision(player *C.fluid_player_t) (int, error) {
+	return int(C.fluid_player_get_division(player)), nil
+}
+
+func setPlayerTempo(player *C.fluid_player_t, t TempoType, bpm float64) error {
+	return fluidStatus(C.fluid_player_set_tempo(player, C.int(t), C.double(bpm)))
+}
+
+// PinPreset keeps the samples of a preset loaded in memory. This only has an effect
+// when "synth.dynamic-sample-loading" is enabled, which is mostly useful for large or
+// compressed (SF3, DLS) fonts whose sample data would otherwise be loaded on first use.
+func (s *Synth) PinPreset(sfontID, bank, program int) error {
+	if C.fluid_synth_pin_preset(s.ptr, C.int(sfontID), C.int(bank), C.int(program)) == C.FLUID_FAILED {
+		return fmt.Errorf("could not pin preset: sfont=%d, bank=%d, program=%d", sfontID, bank, program)
+	}
+	return nil
+}
+
+// UnpinPreset allows a previously pinned preset to be unloaded again
+func (s *Synth) UnpinPreset(sfontID, bank, program int) error {
+	if C.fluid_synth_unpin_preset(s.ptr, C.int(sfontID), C.int(bank), C.int(program)) == C.FLUID_FAILED {
+		return fmt.Errorf("could not unpin preset: sfont=%d, bank=%d, program=%d", sfontID, bank, program)
+	}
+	return nil
+}
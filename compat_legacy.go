@@ -0,0 +1,48 @@
+//go:build fluidsynth21
+
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+var errNeedsFluid22 = fmt.Errorf("not supported by fluidsynth 2.1 and older, build without the fluidsynth21 tag against fluidsynth 2.2 or newer")
+
+// fluidsynth 2.1 has no tick callback, so the player's playlist position, loop region and tempo
+// change callback aren't available
+func setGoTickCallback(player *C.fluid_player_t, data unsafe.Pointer) error {
+	return errNeedsFluid22
+}
+
+func playerDivision(player *C.fluid_player_t) (int, error) {
+	return 0, errNeedsFluid22
+}
+
+// setPlayerTempo uses the tempo setters of fluidsynth 2.1, which take integer values and can't
+// hand the tempo back to the MIDI file once it was set
+func setPlayerTempo(player *C.fluid_player_t, t TempoType, bpm float64) error {
+	switch t {
+	case TEMPO_EXTERNAL_BPM:
+		return fluidStatus(C.fluid_player_set_bpm(player, C.int(bpm+0.5)))
+	case TEMPO_EXTERNAL_MIDI:
+		return fluidStatus(C.fluid_player_set_midi_tempo(player, C.int(bpm+0.5)))
+	default:
+		return errNeedsFluid22
+	}
+}
+
+// PinPreset keeps the samples of a preset loaded in memory.
+// This build has no preset pinning, it always returns an error.
+func (s *Synth) PinPreset(sfontID, bank, program int) error {
+	return errNeedsFluid22
+}
+
+// UnpinPreset allows a previously pinned preset to be unloaded again.
+// This build has no preset pinning, it always returns an error.
+func (s *Synth) UnpinPreset(sfontID, bank, program int) error {
+	return errNeedsFluid22
+}
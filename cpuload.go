@@ -0,0 +1,64 @@
+package fluidsynth2
+
+import (
+	"fmt"
+	"time"
+)
+
+// cpuLoadSamples is the size of the CPU load history, about 6 KiB per synth
+const cpuLoadSamples = 256
+
+type cpuLoadSample struct {
+	at   time.Time
+	load float64
+}
+
+// cpuLoadHistory is a ring buffer of CPU load samples
+type cpuLoadHistory struct {
+	samples [cpuLoadSamples]cpuLoadSample
+	next    int
+	n       int
+}
+
+func (h *cpuLoadHistory) add(at time.Time, load float64) {
+	h.samples[h.next] = cpuLoadSample{at, load}
+	h.next = (h.next + 1) % cpuLoadSamples
+	if h.n < cpuLoadSamples {
+		h.n++
+	}
+}
+
+// average returns the mean of the samples taken within window before now
+func (h *cpuLoadHistory) average(now time.Time, window time.Duration) float64 {
+	var sum float64
+	var count int
+	for i := 1; i <= h.n; i++ {
+		sample := h.samples[(h.next-i+cpuLoadSamples)%cpuLoadSamples]
+		if now.Sub(sample.at) > window {
+			break
+		}
+		sum += sample.load
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// CPULoadAverage samples GetCPULoad and returns the average of the samples taken within window.
+// Samples are only taken when this method is called, so the average covers the calls made
+// during the window; at most the last 256 samples are kept.
+func (s *Synth) CPULoadAverage(window time.Duration) (float64, error) {
+	if window <= 0 {
+		return 0, fmt.Errorf("invalid window: %s", window)
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.closed {
+		return 0, fmt.Errorf("synth is closed")
+	}
+	now := time.Now()
+	s.state.cpuLoad.add(now, s.GetCPULoad())
+	return s.state.cpuLoad.average(now, window), nil
+}
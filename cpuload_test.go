@@ -0,0 +1,53 @@
+package fluidsynth2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCPULoadHistoryAverage(t *testing.T) {
+	start := time.Unix(1000, 0)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	tests := []struct {
+		name    string
+		samples []cpuLoadSample
+		now     time.Time
+		window  time.Duration
+		want    float64
+	}{
+		{"empty", nil, at(0), time.Second, 0},
+		{"single", []cpuLoadSample{{at(0), 40}}, at(0), time.Second, 40},
+		{"all in window", []cpuLoadSample{{at(0), 10}, {at(100), 20}, {at(200), 60}}, at(200), time.Second, 30},
+		{"old samples ignored", []cpuLoadSample{{at(0), 90}, {at(900), 20}, {at(1000), 40}}, at(1000), 500 * time.Millisecond, 30},
+		{"window boundary included", []cpuLoadSample{{at(0), 10}, {at(500), 30}}, at(500), 500 * time.Millisecond, 20},
+		{"all outside window", []cpuLoadSample{{at(0), 10}}, at(2000), time.Second, 0},
+	}
+	for _, tt := range tests {
+		var h cpuLoadHistory
+		for _, s := range tt.samples {
+			h.add(s.at, s.load)
+		}
+		if got := h.average(tt.now, tt.window); got != tt.want {
+			t.Errorf("%s: average = %f, want %f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCPULoadHistoryWraps(t *testing.T) {
+	var h cpuLoadHistory
+	start := time.Unix(1000, 0)
+	// The first samples are overwritten, so only the last cpuLoadSamples loads of 1 count
+	for i := 0; i < cpuLoadSamples+10; i++ {
+		load := 1.0
+		if i < 10 {
+			load = 100
+		}
+		h.add(start.Add(time.Duration(i)*time.Millisecond), load)
+	}
+	if h.n != cpuLoadSamples {
+		t.Errorf("history holds %d samples, want %d", h.n, cpuLoadSamples)
+	}
+	if got := h.average(start.Add(time.Hour), 2*time.Hour); got != 1 {
+		t.Errorf("average = %f, want 1", got)
+	}
+}
@@ -4,17 +4,190 @@ package fluidsynth2
 // #include <fluidsynth.h>
 // #include <stdlib.h>
 import "C"
+import (
+	"fmt"
+	"slices"
+	"unsafe"
+)
 
 type AudioDriver struct {
-	ptr *C.fluid_audio_driver_t
+	ptr      *C.fluid_audio_driver_t
+	settings Settings
+	synth    *C.fluid_synth_t
+	handle   unsafe.Pointer
 }
 
 func NewAudioDriver(settings Settings, synth Synth) AudioDriver {
-	return AudioDriver{C.new_fluid_audio_driver(settings.ptr, synth.ptr)}
+	ptr := C.new_fluid_audio_driver(settings.ptr, synth.ptr)
+	// Close doesn't release the settings of a driver that failed to start
+	if ptr != nil {
+		settings.retain()
+	}
+	return AudioDriver{
+		ptr:      ptr,
+		settings: settings,
+		synth:    synth.ptr,
+	}
+}
+
+// ListAudioDevices returns the output devices fluidsynth can enumerate for an audio driver, as
+// accepted by its "audio.<driver>.device" setting. Enumeration depends on the platform: drivers
+// like coreaudio, dsound, wasapi, waveout and portaudio list their devices, while e.g. alsa,
+// jack, pulseaudio and file take free-form device names and return an empty list. This doesn't
+// use fluid_audio_driver_register, which would change the drivers available to the whole process.
+func ListAudioDevices(driver string) ([]string, error) {
+	settings := NewSettings()
+	defer settings.Close()
+	if !slices.Contains(settings.GetOptions("audio.driver"), driver) {
+		return nil, fmt.Errorf("unknown audio driver: %s", driver)
+	}
+	name := "audio." + driver + ".device"
+	if settings.GetType(name) != SETTING_TYPE_STR {
+		return []string{}, nil
+	}
+	devices := settings.GetOptions(name)
+	if len(devices) == 1 && devices[0] == "" {
+		return []string{}, nil
+	}
+	return devices, nil
+}
+
+// AudioCallback fills the left and right output buffers of an audio driver
+type AudioCallback func(left, right []float32) error
+
+// NewAudioDriverWithCallback creates an audio driver that gets its audio from fn instead of
+// a synth. fn is called on the driver's real-time audio thread whenever it needs a new block;
+// it must fill left and right (usually by rendering a synth with WriteFloat and processing the
+// result) without blocking, allocating heavily or doing I/O, otherwise the output will glitch.
+// Returning an error makes the driver output silence for that block.
+func NewAudioDriverWithCallback(settings *Settings, fn AudioCallback) (AudioDriver, error) {
+	if fn == nil {
+		return AudioDriver{}, fmt.Errorf("nil audio callback")
+	}
+	handle := newCallbackHandle(fn)
+	ptr := newGoAudioDriver(settings.ptr, handle)
+	if ptr == nil {
+		deleteCallbackHandle(handle)
+		return AudioDriver{}, fmt.Errorf("failed to create audio driver")
+	}
+	settings.retain()
+	return AudioDriver{ptr: ptr, settings: *settings, handle: handle}, nil
+}
+
+//export goAudioCallback
+func goAudioCallback(data unsafe.Pointer, length C.int, nfx C.int, fx **C.float, nout C.int, out **C.float) C.int {
+	if nout < 2 {
+		return C.FLUID_FAILED
+	}
+	fn := callbackValue(data).(AudioCallback)
+	outs := unsafe.Slice(out, int(nout))
+	left := unsafe.Slice((*float32)(unsafe.Pointer(outs[0])), int(length))
+	right := unsafe.Slice((*float32)(unsafe.Pointer(outs[1])), int(length))
+	if err := fn(left, right); err != nil {
+		clear(left)
+		clear(right)
+		return C.FLUID_FAILED
+	}
+	return C.FLUID_OK
 }
 
 func (d *AudioDriver) Close() {
+	if d.ptr == nil {
+		return
+	}
+	C.delete_fluid_audio_driver(d.ptr)
+	d.ptr = nil
+	d.settings.release()
+	if d.handle != nil {
+		deleteCallbackHandle(d.handle)
+		d.handle = nil
+	}
+}
+
+// Reconfigure replaces the underlying fluidsynth driver with one created from settings, e.g.
+// after changing "audio.driver" or the output device, keeping the synth or callback it plays.
+// The new driver is created before the old one is deleted, so that the old one keeps playing
+// if creating the new one fails. Outputs that can only be opened once may therefore fail to
+// reconfigure to the same device; close and recreate the driver in that case.
+func (d *AudioDriver) Reconfigure(settings *Settings) error {
+	if d.ptr == nil {
+		return fmt.Errorf("audio driver is closed")
+	}
+	var ptr *C.fluid_audio_driver_t
+	if d.handle != nil {
+		ptr = newGoAudioDriver(settings.ptr, d.handle)
+	} else {
+		ptr = C.new_fluid_audio_driver(settings.ptr, d.synth)
+	}
+	if ptr == nil {
+		return fmt.Errorf("failed to create audio driver")
+	}
 	C.delete_fluid_audio_driver(d.ptr)
+	settings.retain()
+	d.settings.release()
+	d.ptr = ptr
+	d.settings = *settings
+	return nil
+}
+
+type FileFormat int
+
+const (
+	FormatWAV FileFormat = iota
+	FormatFLAC
+	FormatOGG
+)
+
+// fileTypes maps file formats to the values of "audio.file.type"
+var fileTypes = map[FileFormat]string{
+	FormatWAV:  "wav",
+	FormatFLAC: "flac",
+	FormatOGG:  "oga",
+}
+
+type SampleFormat int
+
+const (
+	Sample16 SampleFormat = iota
+	Sample24
+	SampleFloat
+)
+
+// sampleFormats maps sample formats to the values of "audio.file.format"
+var sampleFormats = map[SampleFormat]string{
+	Sample16:    "s16",
+	Sample24:    "s24",
+	SampleFloat: "float",
+}
+
+// ConfigureFileOutput sets up the settings used by a FileRenderer to write to path.
+// The formats fluidsynth supports depend on how it was built: without libsndfile it only
+// writes raw 16-bit files, so both formats are checked against the settings' options first.
+func ConfigureFileOutput(settings *Settings, path string, format FileFormat, sampleFormat SampleFormat) error {
+	fileType, ok := fileTypes[format]
+	if !ok {
+		return fmt.Errorf("invalid file format: %d", format)
+	}
+	sample, ok := sampleFormats[sampleFormat]
+	if !ok {
+		return fmt.Errorf("invalid sample format: %d", sampleFormat)
+	}
+	if !slices.Contains(settings.GetOptions("audio.file.type"), fileType) {
+		return fmt.Errorf("file type not supported by fluidsynth: %s", fileType)
+	}
+	if !slices.Contains(settings.GetOptions("audio.file.format"), sample) {
+		return fmt.Errorf("sample format not supported by fluidsynth: %s", sample)
+	}
+	if !settings.SetString("audio.file.name", path) {
+		return fmt.Errorf("failed to set audio.file.name: %s", path)
+	}
+	if !settings.SetString("audio.file.type", fileType) {
+		return fmt.Errorf("failed to set audio.file.type: %s", fileType)
+	}
+	if !settings.SetString("audio.file.format", sample) {
+		return fmt.Errorf("failed to set audio.file.format: %s", sample)
+	}
+	return nil
 }
 
 type FileRenderer struct {
@@ -0,0 +1,50 @@
+package fluidsynth2
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAudioDriverRetainsSettings(t *testing.T) {
+	settings := NewSettings()
+	settings.SetString("audio.driver", "file")
+	settings.SetString("audio.file.name", filepath.Join(t.TempDir(), "out.wav"))
+	synth := NewSynth(settings)
+	defer synth.Close()
+
+	driver := NewAudioDriver(settings, synth)
+	if driver.ptr == nil {
+		t.Fatal("failed to create audio driver")
+	}
+	if err := settings.Close(); err == nil {
+		t.Fatal("Settings.Close succeeded while an audio driver uses them")
+	}
+	driver.Close()
+	synth.Close()
+	if err := settings.Close(); err != nil {
+		t.Errorf("Settings.Close after closing the driver: %v", err)
+	}
+}
+
+func TestListAudioDevices(t *testing.T) {
+	settings := NewSettings()
+	drivers := settings.GetOptions("audio.driver")
+	settings.Close()
+
+	// every driver has to be listable, whether or not it enumerates devices
+	for _, driver := range drivers {
+		if _, err := ListAudioDevices(driver); err != nil {
+			t.Errorf("ListAudioDevices(%q): %v", driver, err)
+		}
+	}
+	if slices.Contains(drivers, "file") {
+		// the file driver takes a file name, there are no devices to enumerate
+		if devices, err := ListAudioDevices("file"); err != nil || len(devices) != 0 {
+			t.Errorf("ListAudioDevices(\"file\") = %q, %v, want no devices", devices, err)
+		}
+	}
+	if _, err := ListAudioDevices("no-such-driver"); err == nil {
+		t.Error("ListAudioDevices accepted an unknown driver")
+	}
+}
@@ -0,0 +1,58 @@
+package fluidsynth2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DumpState returns a description of the synth's configuration for bug reports, one
+// "key = value" line per value:
+//
+//	gain = 0.2
+//	polyphony = 256
+//	soundfont.1 = FluidR3_GM.sf2
+//	reverb.0 = roomsize=0.2 damping=0 width=0.5 level=0.9
+//	chorus.0 = nr=3 level=2 speed=0.3 depth=8 type=0
+//	channel.0 = sfont=1 bank=0 program=0 volume=100
+//
+// fluidsynth has no getter for the interpolation method, so it isn't included.
+func (s *Synth) DumpState() (string, error) {
+	st, err := s.Snapshot()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "gain = %g\n", st.Gain)
+	fmt.Fprintf(&b, "polyphony = %d\n", st.Polyphony)
+	fmt.Fprintf(&b, "active-voices = %d\n", st.ActiveVoices)
+
+	for i := 0; i < s.SFCount(); i++ {
+		sf, err := s.GetSFont(i)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "soundfont.%d = %s\n", sf.ID(), sf.Name())
+	}
+
+	err = s.ForEachEffectsGroup(func(g int) error {
+		roomsize, damping, width, level, err := s.GetReverb(g)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "reverb.%d = roomsize=%g damping=%g width=%g level=%g\n", g, roomsize, damping, width, level)
+		nr, level, speed, depth, t, err := s.GetChorus(g)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "chorus.%d = nr=%d level=%g speed=%g depth=%g type=%d\n", g, nr, level, speed, depth, t)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for i, ch := range st.Channels {
+		fmt.Fprintf(&b, "channel.%d = sfont=%d bank=%d program=%d volume=%d\n", i, ch.SFontID, ch.Bank, ch.Program, ch.Volume)
+	}
+	return b.String(), nil
+}
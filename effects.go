@@ -0,0 +1,149 @@
+//go:build !fluidsynth21
+
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "fmt"
+
+// setChorus sets all chorus parameters of an effects group, -1 for all groups
+func (s *Synth) setChorus(fxGroup int, nr int, level, speed, depthMs float64, t ChorusType) error {
+	g := C.int(fxGroup)
+	if C.fluid_synth_set_chorus_group_nr(s.ptr, g, C.int(nr)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set chorus voice count: %d", nr)
+	}
+	if C.fluid_synth_set_chorus_group_level(s.ptr, g, C.double(level)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set chorus level: %f", level)
+	}
+	if C.fluid_synth_set_chorus_group_speed(s.ptr, g, C.double(speed)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set chorus speed: %f", speed)
+	}
+	if C.fluid_synth_set_chorus_group_depth(s.ptr, g, C.double(depthMs)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set chorus depth: %f", depthMs)
+	}
+	if C.fluid_synth_set_chorus_group_type(s.ptr, g, C.int(t)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set chorus type: %d", t)
+	}
+	return nil
+}
+
+// setChorusLevel sets the chorus output level of an effects group
+func (s *Synth) setChorusLevel(fxGroup int, level float64) error {
+	return fluidStatus(C.fluid_synth_set_chorus_group_level(s.ptr, C.int(fxGroup), C.double(level)))
+}
+
+// GetChorusNr returns the number of chorus voices of an effects group
+func (s *Synth) GetChorusNr(fxGroup int) (int, error) {
+	var nr C.int
+	if err := fluidStatus(C.fluid_synth_get_chorus_group_nr(s.ptr, C.int(fxGroup), &nr)); err != nil {
+		return 0, err
+	}
+	return int(nr), nil
+}
+
+// GetChorusLevel returns the chorus output level of an effects group
+func (s *Synth) GetChorusLevel(fxGroup int) (float64, error) {
+	var level C.double
+	if err := fluidStatus(C.fluid_synth_get_chorus_group_level(s.ptr, C.int(fxGroup), &level)); err != nil {
+		return 0, err
+	}
+	return float64(level), nil
+}
+
+// GetChorusSpeed returns the chorus modulation speed (in Hz) of an effects group
+func (s *Synth) GetChorusSpeed(fxGroup int) (float64, error) {
+	var speed C.double
+	if err := fluidStatus(C.fluid_synth_get_chorus_group_speed(s.ptr, C.int(fxGroup), &speed)); err != nil {
+		return 0, err
+	}
+	return float64(speed), nil
+}
+
+// GetChorusDepth returns the chorus modulation depth (in ms) of an effects group
+func (s *Synth) GetChorusDepth(fxGroup int) (float64, error) {
+	var depth C.double
+	if err := fluidStatus(C.fluid_synth_get_chorus_group_depth(s.ptr, C.int(fxGroup), &depth)); err != nil {
+		return 0, err
+	}
+	return float64(depth), nil
+}
+
+// GetChorusType returns the chorus waveform of an effects group
+func (s *Synth) GetChorusType(fxGroup int) (ChorusType, error) {
+	var t C.int
+	if err := fluidStatus(C.fluid_synth_get_chorus_group_type(s.ptr, C.int(fxGroup), &t)); err != nil {
+		return 0, err
+	}
+	return ChorusType(t), nil
+}
+
+// setReverb sets all reverb parameters of an effects group, -1 for all groups
+func (s *Synth) setReverb(fxGroup int, roomsize, damping, width, level float64) error {
+	g := C.int(fxGroup)
+	if C.fluid_synth_set_reverb_group_roomsize(s.ptr, g, C.double(roomsize)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set reverb room size: %f", roomsize)
+	}
+	if C.fluid_synth_set_reverb_group_damp(s.ptr, g, C.double(damping)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set reverb damping: %f", damping)
+	}
+	if C.fluid_synth_set_reverb_group_width(s.ptr, g, C.double(width)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set reverb width: %f", width)
+	}
+	if C.fluid_synth_set_reverb_group_level(s.ptr, g, C.double(level)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set reverb level: %f", level)
+	}
+	return nil
+}
+
+// setReverbLevel sets the reverb output level of an effects group
+func (s *Synth) setReverbLevel(fxGroup int, level float64) error {
+	return fluidStatus(C.fluid_synth_set_reverb_group_level(s.ptr, C.int(fxGroup), C.double(level)))
+}
+
+// GetReverbRoomsize returns the reverb room size of an effects group
+func (s *Synth) GetReverbRoomsize(fxGroup int) (float64, error) {
+	var roomsize C.double
+	if err := fluidStatus(C.fluid_synth_get_reverb_group_roomsize(s.ptr, C.int(fxGroup), &roomsize)); err != nil {
+		return 0, err
+	}
+	return float64(roomsize), nil
+}
+
+// GetReverbDamp returns the reverb damping of an effects group
+func (s *Synth) GetReverbDamp(fxGroup int) (float64, error) {
+	var damping C.double
+	if err := fluidStatus(C.fluid_synth_get_reverb_group_damp(s.ptr, C.int(fxGroup), &damping)); err != nil {
+		return 0, err
+	}
+	return float64(damping), nil
+}
+
+// GetReverbWidth returns the reverb width of an effects group
+func (s *Synth) GetReverbWidth(fxGroup int) (float64, error) {
+	var width C.double
+	if err := fluidStatus(C.fluid_synth_get_reverb_group_width(s.ptr, C.int(fxGroup), &width)); err != nil {
+		return 0, err
+	}
+	return float64(width), nil
+}
+
+// GetReverbLevel returns the reverb output level of an effects group
+func (s *Synth) GetReverbLevel(fxGroup int) (float64, error) {
+	var level C.double
+	if err := fluidStatus(C.fluid_synth_get_reverb_group_level(s.ptr, C.int(fxGroup), &level)); err != nil {
+		return 0, err
+	}
+	return float64(level), nil
+}
+
+// ReverbOn enables or disables the reverb of an effects group, -1 for all groups
+func (s *Synth) ReverbOn(fxGroup int, on bool) error {
+	return fluidStatus(C.fluid_synth_reverb_on(s.ptr, C.int(fxGroup), cbool(on)))
+}
+
+// ChorusOn enables or disables the chorus of an effects group, -1 for all groups
+func (s *Synth) ChorusOn(fxGroup int, on bool) error {
+	return fluidStatus(C.fluid_synth_chorus_on(s.ptr, C.int(fxGroup), cbool(on)))
+}
@@ -0,0 +1,406 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type ChorusType int
+
+const (
+	CHORUS_MOD_SINE     ChorusType = C.FLUID_CHORUS_MOD_SINE
+	CHORUS_MOD_TRIANGLE ChorusType = C.FLUID_CHORUS_MOD_TRIANGLE
+)
+
+// SetChorus sets all chorus parameters of an effects group in one call.
+// An fxGroup of -1 applies the parameters to all effects groups. The parameters are checked
+// against the ranges of the "synth.chorus.*" settings first, and if fluidsynth still rejects
+// one of them the parameters it already applied are rolled back.
+func (s *Synth) SetChorus(fxGroup int, nr int, level, speed, depthMs float64, t ChorusType) error {
+	if t != CHORUS_MOD_SINE && t != CHORUS_MOD_TRIANGLE {
+		return fmt.Errorf("invalid chorus type: %d", t)
+	}
+	if err := errors.Join(
+		s.validateEffectsGroup(fxGroup),
+		s.settings.ValidateInt("synth.chorus.nr", nr),
+		s.settings.ValidateNum("synth.chorus.level", level),
+		s.settings.ValidateNum("synth.chorus.speed", speed),
+		s.settings.ValidateNum("synth.chorus.depth", depthMs),
+	); err != nil {
+		return err
+	}
+	groups := s.affectedEffectsGroups(fxGroup)
+	old := make([]ChorusPreset, len(groups))
+	for i, g := range groups {
+		p := &old[i]
+		var err error
+		if p.Nr, p.Level, p.Speed, p.Depth, p.Type, err = s.GetChorus(g); err != nil {
+			return fmt.Errorf("failed to get chorus of effects group %d", g)
+		}
+	}
+	if err := s.setChorus(fxGroup, nr, level, speed, depthMs, t); err != nil {
+		for i, g := range groups {
+			p := old[i]
+			s.setChorus(g, p.Nr, p.Level, p.Speed, p.Depth, p.Type)
+		}
+		return err
+	}
+	return nil
+}
+
+// SetReverb sets all reverb parameters of an effects group in one call.
+// An fxGroup of -1 applies the parameters to all effects groups. Like SetChorus the parameters
+// are validated first and rolled back if fluidsynth rejects one of them.
+func (s *Synth) SetReverb(fxGroup int, roomsize, damping, width, level float64) error {
+	if err := errors.Join(
+		s.validateEffectsGroup(fxGroup),
+		s.settings.ValidateNum("synth.reverb.room-size", roomsize),
+		s.settings.ValidateNum("synth.reverb.damp", damping),
+		s.settings.ValidateNum("synth.reverb.width", width),
+		s.settings.ValidateNum("synth.reverb.level", level),
+	); err != nil {
+		return err
+	}
+	groups := s.affectedEffectsGroups(fxGroup)
+	old := make([]ReverbPreset, len(groups))
+	for i, g := range groups {
+		p := &old[i]
+		var err error
+		if p.Roomsize, p.Damping, p.Width, p.Level, err = s.GetReverb(g); err != nil {
+			return fmt.Errorf("failed to get reverb of effects group %d", g)
+		}
+	}
+	if err := s.setReverb(fxGroup, roomsize, damping, width, level); err != nil {
+		for i, g := range groups {
+			p := old[i]
+			s.setReverb(g, p.Roomsize, p.Damping, p.Width, p.Level)
+		}
+		return err
+	}
+	return nil
+}
+
+// validateEffectsGroup checks that fxGroup is an effects group of the synth or -1 for all groups
+func (s *Synth) validateEffectsGroup(fxGroup int) error {
+	if fxGroup < -1 || fxGroup >= s.CountEffectsGroups() {
+		return fmt.Errorf("invalid effects group: %d", fxGroup)
+	}
+	return nil
+}
+
+// affectedEffectsGroups returns the effects groups a setter called with fxGroup changes
+func (s *Synth) affectedEffectsGroups(fxGroup int) []int {
+	if fxGroup != -1 {
+		return []int{fxGroup}
+	}
+	groups := make([]int, s.CountEffectsGroups())
+	for g := range groups {
+		groups[g] = g
+	}
+	return groups
+}
+
+// SetReverbParams sets the reverb parameters of all effects groups
+func (s *Synth) SetReverbParams(roomsize, damping, width, level float64) error {
+	status := C.fluid_synth_set_reverb(s.ptr, C.double(roomsize), C.double(damping), C.double(width), C.double(level))
+	if status == C.FLUID_FAILED {
+		return fmt.Errorf("failed to set reverb: roomsize=%f, damping=%f, width=%f, level=%f", roomsize, damping, width, level)
+	}
+	return nil
+}
+
+// GetReverbParams returns the global reverb parameters
+func (s *Synth) GetReverbParams() (roomsize, damping, width, level float64) {
+	roomsize = float64(C.fluid_synth_get_reverb_roomsize(s.ptr))
+	damping = float64(C.fluid_synth_get_reverb_damp(s.ptr))
+	width = float64(C.fluid_synth_get_reverb_width(s.ptr))
+	level = float64(C.fluid_synth_get_reverb_level(s.ptr))
+	return roomsize, damping, width, level
+}
+
+// ReverbPreset is a named set of reverb parameters that can be applied with ApplyReverbPreset
+type ReverbPreset struct {
+	Name     string
+	Roomsize float64
+	Damping  float64
+	Width    float64
+	Level    float64
+}
+
+// ChorusPreset is a named set of chorus parameters that can be applied with ApplyChorusPreset
+type ChorusPreset struct {
+	Name  string
+	Nr    int
+	Level float64
+	Speed float64
+	Depth float64
+	Type  ChorusType
+}
+
+var (
+	ReverbRoom      = ReverbPreset{Name: "Room", Roomsize: 0.4, Damping: 0.4, Width: 0.5, Level: 0.6}
+	ReverbHall      = ReverbPreset{Name: "Hall", Roomsize: 0.7, Damping: 0.3, Width: 0.8, Level: 0.7}
+	ReverbPlate     = ReverbPreset{Name: "Plate", Roomsize: 0.5, Damping: 0.1, Width: 1.0, Level: 0.8}
+	ReverbCathedral = ReverbPreset{Name: "Cathedral", Roomsize: 0.95, Damping: 0.2, Width: 1.0, Level: 0.8}
+
+	ChorusLight    = ChorusPreset{Name: "Light", Nr: 3, Level: 1.0, Speed: 0.3, Depth: 4.0, Type: CHORUS_MOD_SINE}
+	ChorusStandard = ChorusPreset{Name: "Standard", Nr: 3, Level: 2.0, Speed: 0.3, Depth: 8.0, Type: CHORUS_MOD_SINE}
+	ChorusEnsemble = ChorusPreset{Name: "Ensemble", Nr: 5, Level: 2.0, Speed: 0.5, Depth: 12.0, Type: CHORUS_MOD_TRIANGLE}
+	ChorusDeep     = ChorusPreset{Name: "Deep", Nr: 6, Level: 3.0, Speed: 0.2, Depth: 20.0, Type: CHORUS_MOD_SINE}
+)
+
+// ApplyReverbPreset sets the reverb parameters of an effects group to the values of a preset
+func (s *Synth) ApplyReverbPreset(fxGroup int, p ReverbPreset) error {
+	return s.SetReverb(fxGroup, p.Roomsize, p.Damping, p.Width, p.Level)
+}
+
+// ReverbPresets are the reverb presets MatchReverbPreset compares against
+var ReverbPresets = []ReverbPreset{ReverbRoom, ReverbHall, ReverbPlate, ReverbCathedral}
+
+const (
+	// reverbExactTolerance absorbs the rounding of parameters stored by fluidsynth
+	reverbExactTolerance = 1e-6
+	// reverbMatchTolerance is the largest parameter difference MatchReverbPreset accepts
+	reverbMatchTolerance = 0.05
+)
+
+// MatchReverbPreset returns the name of the preset in ReverbPresets closest to the reverb of an
+// effects group, and whether it matches exactly. Presets are compared by the largest difference
+// of any parameter; if no preset is within 0.05 of every parameter the name is "custom".
+func (s *Synth) MatchReverbPreset(fxGroup int) (name string, exact bool, err error) {
+	roomsize, damping, width, level, err := s.GetReverb(fxGroup)
+	if err != nil {
+		return "", false, err
+	}
+	best, bestDiff := "custom", math.Inf(1)
+	for _, p := range ReverbPresets {
+		diff := max(math.Abs(p.Roomsize-roomsize), math.Abs(p.Damping-damping),
+			math.Abs(p.Width-width), math.Abs(p.Level-level))
+		if diff <= reverbMatchTolerance && diff < bestDiff {
+			best, bestDiff = p.Name, diff
+		}
+	}
+	return best, bestDiff <= reverbExactTolerance, nil
+}
+
+// ApplyChorusPreset sets the chorus parameters of an effects group to the values of a preset
+func (s *Synth) ApplyChorusPreset(fxGroup int, p ChorusPreset) error {
+	return s.SetChorus(fxGroup, p.Nr, p.Level, p.Speed, p.Depth, p.Type)
+}
+
+// ResetReverb restores the reverb parameters of an effects group to the defaults of the
+// "synth.reverb.*" settings (room-size 0.2, damp 0.0, width 0.5 and level 0.9 in fluidsynth 2.x)
+func (s *Synth) ResetReverb(fxGroup int) error {
+	var roomsize, damping, width, level float64
+	if !s.settings.GetNumDefault("synth.reverb.room-size", &roomsize) ||
+		!s.settings.GetNumDefault("synth.reverb.damp", &damping) ||
+		!s.settings.GetNumDefault("synth.reverb.width", &width) ||
+		!s.settings.GetNumDefault("synth.reverb.level", &level) {
+		return fmt.Errorf("failed to read reverb defaults")
+	}
+	return s.SetReverb(fxGroup, roomsize, damping, width, level)
+}
+
+// ResetChorus restores the chorus parameters of an effects group to the defaults of the
+// "synth.chorus.*" settings (nr 3, level 2.0, speed 0.3 and depth 8.0 in fluidsynth 2.x) and a sine wave
+func (s *Synth) ResetChorus(fxGroup int) error {
+	var nr int
+	var level, speed, depth float64
+	if !s.settings.GetIntDefault("synth.chorus.nr", &nr) ||
+		!s.settings.GetNumDefault("synth.chorus.level", &level) ||
+		!s.settings.GetNumDefault("synth.chorus.speed", &speed) ||
+		!s.settings.GetNumDefault("synth.chorus.depth", &depth) {
+		return fmt.Errorf("failed to read chorus defaults")
+	}
+	return s.SetChorus(fxGroup, nr, level, speed, depth, CHORUS_MOD_SINE)
+}
+
+// CountEffectsGroups returns the number of effects groups of the synth
+func (s *Synth) CountEffectsGroups() int {
+	return int(C.fluid_synth_count_effects_groups(s.ptr))
+}
+
+// ForEachEffectsGroup calls fn for every effects group, stopping at the first error
+func (s *Synth) ForEachEffectsGroup(fn func(group int) error) error {
+	n := s.CountEffectsGroups()
+	for g := 0; g < n; g++ {
+		if err := fn(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetReverbAll sets the same reverb parameters on every effects group, one group at a time.
+// It is equivalent to SetReverb with fxGroup -1, but reports which group failed.
+// Groups can still be adjusted individually with SetReverb afterwards.
+func (s *Synth) SetReverbAll(roomsize, damping, width, level float64) error {
+	return s.ForEachEffectsGroup(func(g int) error {
+		if err := s.SetReverb(g, roomsize, damping, width, level); err != nil {
+			return fmt.Errorf("effects group %d: %w", g, err)
+		}
+		return nil
+	})
+}
+
+// SetChorusAll sets the same chorus parameters on every effects group, see SetReverbAll
+func (s *Synth) SetChorusAll(nr int, level, speed, depthMs float64, t ChorusType) error {
+	return s.ForEachEffectsGroup(func(g int) error {
+		if err := s.SetChorus(g, nr, level, speed, depthMs, t); err != nil {
+			return fmt.Errorf("effects group %d: %w", g, err)
+		}
+		return nil
+	})
+}
+
+// SetReverbActive enables or disables the reverb globally. The "synth.reverb.active" setting is
+// what new synths created from the same Settings start with, while ReverbOn switches the reverb
+// of this synth right away; SetReverbActive updates both, for every effects group.
+func (s *Synth) SetReverbActive(on bool) error {
+	if !s.settings.SetInt("synth.reverb.active", int(cbool(on))) {
+		return fmt.Errorf("failed to set synth.reverb.active")
+	}
+	return s.ForEachEffectsGroup(func(g int) error {
+		if err := s.ReverbOn(g, on); err != nil {
+			return fmt.Errorf("failed to switch reverb of effects group %d", g)
+		}
+		return nil
+	})
+}
+
+// SetChorusActive enables or disables the chorus globally, see SetReverbActive
+func (s *Synth) SetChorusActive(on bool) error {
+	if !s.settings.SetInt("synth.chorus.active", int(cbool(on))) {
+		return fmt.Errorf("failed to set synth.chorus.active")
+	}
+	return s.ForEachEffectsGroup(func(g int) error {
+		if err := s.ChorusOn(g, on); err != nil {
+			return fmt.Errorf("failed to switch chorus of effects group %d", g)
+		}
+		return nil
+	})
+}
+
+// BypassReverb switches the reverb of all effects groups off (on = true) or back on. Unlike
+// ResetReverb it doesn't touch the reverb parameters, so re-enabling restores the same sound.
+func (s *Synth) BypassReverb(on bool) error {
+	if err := s.ReverbOn(-1, !on); err != nil {
+		return fmt.Errorf("failed to bypass reverb")
+	}
+	s.state.mu.Lock()
+	s.state.reverbBypassed = on
+	s.state.mu.Unlock()
+	return nil
+}
+
+// BypassChorus switches the chorus of all effects groups off or back on, see BypassReverb
+func (s *Synth) BypassChorus(on bool) error {
+	if err := s.ChorusOn(-1, !on); err != nil {
+		return fmt.Errorf("failed to bypass chorus")
+	}
+	s.state.mu.Lock()
+	s.state.chorusBypassed = on
+	s.state.mu.Unlock()
+	return nil
+}
+
+// ReverbBypassed returns true if the reverb was bypassed with BypassReverb
+func (s *Synth) ReverbBypassed() bool {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.reverbBypassed
+}
+
+// ChorusBypassed returns true if the chorus was bypassed with BypassChorus
+func (s *Synth) ChorusBypassed() bool {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.chorusBypassed
+}
+
+type effectLevels struct {
+	reverb, chorus float64
+}
+
+// SetMasterWet scales the reverb and chorus levels of all effects groups by wet, from 0.0 (dry)
+// to 1.0 (the levels as set). The levels are remembered when wet first drops below 1.0 and
+// restored when it returns to 1.0; levels changed with SetReverb or SetChorus in between are
+// overwritten by the next SetMasterWet call.
+func (s *Synth) SetMasterWet(wet float64) error {
+	if math.IsNaN(wet) || wet < 0 || wet > 1 {
+		return fmt.Errorf("invalid wet amount: %f", wet)
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.wetLevels == nil {
+		levels := make([]effectLevels, s.CountEffectsGroups())
+		for g := range levels {
+			var err error
+			if levels[g].reverb, err = s.GetReverbLevel(g); err != nil {
+				return fmt.Errorf("failed to get reverb level of effects group %d", g)
+			}
+			if levels[g].chorus, err = s.GetChorusLevel(g); err != nil {
+				return fmt.Errorf("failed to get chorus level of effects group %d", g)
+			}
+		}
+		s.state.wetLevels = levels
+	}
+	for g, l := range s.state.wetLevels {
+		if err := s.setReverbLevel(g, l.reverb*wet); err != nil {
+			return fmt.Errorf("failed to set reverb level of effects group %d", g)
+		}
+		if err := s.setChorusLevel(g, l.chorus*wet); err != nil {
+			return fmt.Errorf("failed to set chorus level of effects group %d", g)
+		}
+	}
+	s.state.wet = wet
+	if wet == 1 {
+		s.state.wetLevels = nil
+	}
+	return nil
+}
+
+// GetMasterWet returns the dry/wet amount set with SetMasterWet, 1.0 by default
+func (s *Synth) GetMasterWet() float64 {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.wet
+}
+
+// GetReverb returns all reverb parameters of an effects group
+func (s *Synth) GetReverb(fxGroup int) (roomsize, damping, width, level float64, err error) {
+	if roomsize, err = s.GetReverbRoomsize(fxGroup); err != nil {
+		return
+	}
+	if damping, err = s.GetReverbDamp(fxGroup); err != nil {
+		return
+	}
+	if width, err = s.GetReverbWidth(fxGroup); err != nil {
+		return
+	}
+	level, err = s.GetReverbLevel(fxGroup)
+	return
+}
+
+// GetChorus returns all chorus parameters of an effects group
+func (s *Synth) GetChorus(fxGroup int) (nr int, level, speed, depth float64, t ChorusType, err error) {
+	if nr, err = s.GetChorusNr(fxGroup); err != nil {
+		return
+	}
+	if level, err = s.GetChorusLevel(fxGroup); err != nil {
+		return
+	}
+	if speed, err = s.GetChorusSpeed(fxGroup); err != nil {
+		return
+	}
+	if depth, err = s.GetChorusDepth(fxGroup); err != nil {
+		return
+	}
+	t, err = s.GetChorusType(fxGroup)
+	return
+}
@@ -0,0 +1,49 @@
+//go:build fluidsynth21
+
+package fluidsynth2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLegacyReverb(t *testing.T) {
+	synth := newTestSynth(t)
+	if err := synth.SetReverb(0, 0.7, 0.3, 0.8, 0.6); err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []int{-1, 0} {
+		roomsize, damping, width, level, err := synth.GetReverb(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []float64{roomsize, damping, width, level}
+		for i, want := range []float64{0.7, 0.3, 0.8, 0.6} {
+			if math.Abs(got[i]-want) > reverbExactTolerance {
+				t.Errorf("group %d: got reverb %v, want %v", g, got, []float64{0.7, 0.3, 0.8, 0.6})
+				break
+			}
+		}
+	}
+	roomsize, _, _, _ := synth.GetReverbParams()
+	if math.Abs(roomsize-0.7) > reverbExactTolerance {
+		t.Errorf("GetReverbParams room size = %f, want 0.7", roomsize)
+	}
+}
+
+func TestLegacyChorus(t *testing.T) {
+	synth := newTestSynth(t)
+	if err := synth.SetChorus(-1, 5, 2.0, 0.5, 12.0, CHORUS_MOD_TRIANGLE); err != nil {
+		t.Fatal(err)
+	}
+	nr, level, speed, depth, typ, err := synth.GetChorus(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nr != 5 || level != 2.0 || speed != 0.5 || depth != 12.0 || typ != CHORUS_MOD_TRIANGLE {
+		t.Errorf("got chorus %d %f %f %f %d, want 5 2.0 0.5 12.0 %d", nr, level, speed, depth, typ, CHORUS_MOD_TRIANGLE)
+	}
+	if err := synth.ChorusOn(-1, false); err != nil {
+		t.Errorf("ChorusOn: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package fluidsynth2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSetChorus(t *testing.T) {
+	synth := newTestSynth(t)
+	if err := synth.SetChorus(-1, 5, 2.5, 0.5, 12.0, CHORUS_MOD_TRIANGLE); err != nil {
+		t.Fatal(err)
+	}
+	nr, level, speed, depth, typ, err := synth.GetChorus(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nr != 5 || level != 2.5 || speed != 0.5 || depth != 12.0 || typ != CHORUS_MOD_TRIANGLE {
+		t.Errorf("got chorus %d %f %f %f %d, want 5 2.5 0.5 12.0 %d", nr, level, speed, depth, typ, CHORUS_MOD_TRIANGLE)
+	}
+}
+
+func TestSetReverbInvalid(t *testing.T) {
+	synth := newTestSynth(t)
+	if err := synth.SetReverb(-1, 0.4, 0.4, 0.5, 0.6); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name                            string
+		group                           int
+		roomsize, damping, width, level float64
+	}{
+		{"room size", -1, 2.0, 0.4, 0.5, 0.6},
+		{"damping", -1, 0.4, -1.0, 0.5, 0.6},
+		{"width", -1, 0.4, 0.4, 1000, 0.6},
+		{"level", -1, 0.4, 0.4, 0.5, 2.0},
+		{"group", synth.CountEffectsGroups(), 0.9, 0.9, 0.9, 0.9},
+	}
+	for _, tt := range tests {
+		if err := synth.SetReverb(tt.group, tt.roomsize, tt.damping, tt.width, tt.level); err == nil {
+			t.Errorf("%s: SetReverb accepted an invalid value", tt.name)
+		}
+		roomsize, damping, width, level, err := synth.GetReverb(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(roomsize-0.4) > reverbExactTolerance || math.Abs(damping-0.4) > reverbExactTolerance ||
+			math.Abs(width-0.5) > reverbExactTolerance || math.Abs(level-0.6) > reverbExactTolerance {
+			t.Errorf("%s: reverb changed to %f %f %f %f", tt.name, roomsize, damping, width, level)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package fluidsynth2
+
+/*
+#cgo pkg-config: fluidsynth
+#include <fluidsynth.h>
+#include <stdlib.h>
+
+typedef struct {
+	int type;
+	int channel;
+	int p1;
+	int p2;
+} raw_midi_event;
+
+static void send_raw_events(fluid_synth_t *synth, raw_midi_event *ev, int n, int *status) {
+	int i;
+	for (i = 0; i < n; i++) {
+		switch (ev[i].type) {
+		case 0x80:
+			status[i] = fluid_synth_noteoff(synth, ev[i].channel, ev[i].p1);
+			break;
+		case 0x90:
+			status[i] = fluid_synth_noteon(synth, ev[i].channel, ev[i].p1, ev[i].p2);
+			break;
+		case 0xa0:
+			status[i] = fluid_synth_key_pressure(synth, ev[i].channel, ev[i].p1, ev[i].p2);
+			break;
+		case 0xb0:
+			status[i] = fluid_synth_cc(synth, ev[i].channel, ev[i].p1, ev[i].p2);
+			break;
+		case 0xc0:
+			status[i] = fluid_synth_program_change(synth, ev[i].channel, ev[i].p1);
+			break;
+		case 0xd0:
+			status[i] = fluid_synth_channel_pressure(synth, ev[i].channel, ev[i].p1);
+			break;
+		case 0xe0:
+			status[i] = fluid_synth_pitch_bend(synth, ev[i].channel, ev[i].p1);
+			break;
+		default:
+			status[i] = FLUID_FAILED;
+		}
+	}
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+)
+
+// EventType is the status byte of a MIDI channel message, without the channel
+type EventType int
+
+const (
+	NOTE_OFF         EventType = 0x80
+	NOTE_ON          EventType = 0x90
+	KEY_PRESSURE     EventType = 0xa0
+	CONTROL_CHANGE   EventType = 0xb0
+	PROGRAM_CHANGE   EventType = 0xc0
+	CHANNEL_PRESSURE EventType = 0xd0
+	PITCH_BEND       EventType = 0xe0
+
+	// system messages, as found in MIDI files and passed to the player's playback callback
+	SYSEX        EventType = 0xf0
+	SYSTEM_RESET EventType = 0xff
+)
+
+func (t EventType) String() string {
+	switch t {
+	case NOTE_OFF:
+		return "NoteOff"
+	case NOTE_ON:
+		return "NoteOn"
+	case KEY_PRESSURE:
+		return "KeyPressure"
+	case CONTROL_CHANGE:
+		return "ControlChange"
+	case PROGRAM_CHANGE:
+		return "ProgramChange"
+	case CHANNEL_PRESSURE:
+		return "ChannelPressure"
+	case PITCH_BEND:
+		return "PitchBend"
+	case SET_TEMPO:
+		return "SetTempo"
+	case SYSEX:
+		return "SysEx"
+	case SYSTEM_RESET:
+		return "SystemReset"
+	default:
+		return fmt.Sprintf("EventType(0x%02x)", int(t))
+	}
+}
+
+// RawMIDIEvent is a MIDI channel message. P1 is the key, controller, program, pressure
+// or 14-bit pitch bend value depending on Type, P2 the velocity, controller value or key pressure.
+type RawMIDIEvent struct {
+	Type    EventType
+	Channel uint8
+	P1, P2  int
+}
+
+func (e RawMIDIEvent) validate() error {
+	max1, max2 := 127, 127
+	switch e.Type {
+	case PROGRAM_CHANGE, CHANNEL_PRESSURE:
+		max2 = 0
+	case PITCH_BEND:
+		max1, max2 = 16383, 0
+	case NOTE_OFF, NOTE_ON, KEY_PRESSURE, CONTROL_CHANGE:
+	default:
+		return fmt.Errorf("unsupported event type: %#x", int(e.Type))
+	}
+	if e.P1 < 0 || e.P1 > max1 || e.P2 < 0 || e.P2 > max2 {
+		return fmt.Errorf("invalid event parameters: type=%#x, p1=%d, p2=%d", int(e.Type), e.P1, e.P2)
+	}
+	return nil
+}
+
+// SendEvents sends a batch of MIDI events to the synth. The events are validated and
+// then dispatched in a single cgo call, which is much cheaper than calling NoteOn, CC, etc.
+// for every event. Invalid or failed events don't stop the remaining events, all errors are
+// returned together.
+func (s *Synth) SendEvents(events []RawMIDIEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	var errs []error
+	cevents := make([]C.raw_midi_event, 0, len(events))
+	sent := make([]int, 0, len(events))
+	for i, e := range events {
+		if err := e.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("event %d: %w", i, err))
+			continue
+		}
+		switch e.Type {
+		case NOTE_ON:
+			if e.P2 > 0 && s.isMuted(e.Channel) {
+				continue
+			}
+			e.P2 = int(s.applyVelocityCurve(e.Channel, uint8(e.P2)))
+		case CHANNEL_PRESSURE:
+			e.P1 = s.scalePressure(e.Channel, e.P1)
+		case KEY_PRESSURE:
+			e.P2 = s.scalePressure(e.Channel, e.P2)
+		}
+		cevents = append(cevents, C.raw_midi_event{
+			_type:   C.int(e.Type),
+			channel: C.int(e.Channel),
+			p1:      C.int(e.P1),
+			p2:      C.int(e.P2),
+		})
+		sent = append(sent, i)
+	}
+	if len(cevents) > 0 {
+		status := make([]C.int, len(cevents))
+		C.send_raw_events(s.ptr, &cevents[0], C.int(len(cevents)), &status[0])
+		for j, st := range status {
+			e := events[sent[j]]
+			if st == C.FLUID_FAILED {
+				errs = append(errs, fmt.Errorf("event %d: failed to send: type=%#x, channel=%d, p1=%d, p2=%d", sent[j], int(e.Type), e.Channel, e.P1, e.P2))
+			} else if e.Type == NOTE_OFF || e.Type == NOTE_ON && e.P2 == 0 {
+				s.stopNote(e.Channel, uint8(e.P1))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
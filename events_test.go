@@ -0,0 +1,73 @@
+package fluidsynth2
+
+import "testing"
+
+func TestRawMIDIEventValidate(t *testing.T) {
+	tests := []struct {
+		event RawMIDIEvent
+		valid bool
+	}{
+		{RawMIDIEvent{Type: NOTE_ON, P1: 60, P2: 100}, true},
+		{RawMIDIEvent{Type: NOTE_ON, P1: 128, P2: 100}, false},
+		{RawMIDIEvent{Type: NOTE_OFF, P1: 60, P2: 64}, true},
+		{RawMIDIEvent{Type: NOTE_OFF, P1: 60, P2: 127}, true},
+		{RawMIDIEvent{Type: NOTE_OFF, P1: 60, P2: 128}, false},
+		{RawMIDIEvent{Type: KEY_PRESSURE, P1: 60, P2: -1}, false},
+		{RawMIDIEvent{Type: CONTROL_CHANGE, P1: CC_VOLUME, P2: 127}, true},
+		{RawMIDIEvent{Type: PROGRAM_CHANGE, P1: 127}, true},
+		{RawMIDIEvent{Type: PROGRAM_CHANGE, P1: 1, P2: 1}, false},
+		{RawMIDIEvent{Type: CHANNEL_PRESSURE, P1: 127}, true},
+		{RawMIDIEvent{Type: PITCH_BEND, P1: 16383}, true},
+		{RawMIDIEvent{Type: PITCH_BEND, P1: 16384}, false},
+		{RawMIDIEvent{Type: SYSEX}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.event.validate(); (err == nil) != tt.valid {
+			t.Errorf("%v: validate() = %v, want valid %t", tt.event, err, tt.valid)
+		}
+	}
+}
+
+// benchmarkEvents are note-ons and note-offs for every key on channel 0
+var benchmarkEvents = func() []RawMIDIEvent {
+	events := make([]RawMIDIEvent, 0, 256)
+	for key := 0; key < 128; key++ {
+		events = append(events, RawMIDIEvent{Type: NOTE_ON, P1: key, P2: 100})
+	}
+	for key := 0; key < 128; key++ {
+		events = append(events, RawMIDIEvent{Type: NOTE_OFF, P1: key, P2: 64})
+	}
+	return events
+}()
+
+func BenchmarkValidate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, e := range benchmarkEvents {
+			if err := e.validate(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkNoteOnOff(b *testing.B) {
+	synth := newTestSynth(b)
+	for i := 0; i < b.N; i++ {
+		for _, e := range benchmarkEvents {
+			if e.Type == NOTE_OFF {
+				synth.NoteOff(e.Channel, uint8(e.P1))
+			} else if err := synth.NoteOn(e.Channel, uint8(e.P1), uint8(e.P2)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkSendEvents(b *testing.B) {
+	synth := newTestSynth(b)
+	for i := 0; i < b.N; i++ {
+		if err := synth.SendEvents(benchmarkEvents); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "fmt"
+
+type GenType int
+
+// SoundFont 2 generators, as used for modulator destinations and per-channel generator values
+const (
+	GEN_STARTADDROFS           GenType = C.GEN_STARTADDROFS
+	GEN_ENDADDROFS             GenType = C.GEN_ENDADDROFS
+	GEN_STARTLOOPADDROFS       GenType = C.GEN_STARTLOOPADDROFS
+	GEN_ENDLOOPADDROFS         GenType = C.GEN_ENDLOOPADDROFS
+	GEN_STARTADDRCOARSEOFS     GenType = C.GEN_STARTADDRCOARSEOFS
+	GEN_MODLFOTOPITCH          GenType = C.GEN_MODLFOTOPITCH
+	GEN_VIBLFOTOPITCH          GenType = C.GEN_VIBLFOTOPITCH
+	GEN_MODENVTOPITCH          GenType = C.GEN_MODENVTOPITCH
+	GEN_FILTERFC               GenType = C.GEN_FILTERFC
+	GEN_FILTERQ                GenType = C.GEN_FILTERQ
+	GEN_MODLFOTOFILTERFC       GenType = C.GEN_MODLFOTOFILTERFC
+	GEN_MODENVTOFILTERFC       GenType = C.GEN_MODENVTOFILTERFC
+	GEN_ENDADDRCOARSEOFS       GenType = C.GEN_ENDADDRCOARSEOFS
+	GEN_MODLFOTOVOL            GenType = C.GEN_MODLFOTOVOL
+	GEN_UNUSED1                GenType = C.GEN_UNUSED1
+	GEN_CHORUSSEND             GenType = C.GEN_CHORUSSEND
+	GEN_REVERBSEND             GenType = C.GEN_REVERBSEND
+	GEN_PAN                    GenType = C.GEN_PAN
+	GEN_UNUSED2                GenType = C.GEN_UNUSED2
+	GEN_UNUSED3                GenType = C.GEN_UNUSED3
+	GEN_UNUSED4                GenType = C.GEN_UNUSED4
+	GEN_MODLFODELAY            GenType = C.GEN_MODLFODELAY
+	GEN_MODLFOFREQ             GenType = C.GEN_MODLFOFREQ
+	GEN_VIBLFODELAY            GenType = C.GEN_VIBLFODELAY
+	GEN_VIBLFOFREQ             GenType = C.GEN_VIBLFOFREQ
+	GEN_MODENVDELAY            GenType = C.GEN_MODENVDELAY
+	GEN_MODENVATTACK           GenType = C.GEN_MODENVATTACK
+	GEN_MODENVHOLD             GenType = C.GEN_MODENVHOLD
+	GEN_MODENVDECAY            GenType = C.GEN_MODENVDECAY
+	GEN_MODENVSUSTAIN          GenType = C.GEN_MODENVSUSTAIN
+	GEN_MODENVRELEASE          GenType = C.GEN_MODENVRELEASE
+	GEN_KEYTOMODENVHOLD        GenType = C.GEN_KEYTOMODENVHOLD
+	GEN_KEYTOMODENVDECAY       GenType = C.GEN_KEYTOMODENVDECAY
+	GEN_VOLENVDELAY            GenType = C.GEN_VOLENVDELAY
+	GEN_VOLENVATTACK           GenType = C.GEN_VOLENVATTACK
+	GEN_VOLENVHOLD             GenType = C.GEN_VOLENVHOLD
+	GEN_VOLENVDECAY            GenType = C.GEN_VOLENVDECAY
+	GEN_VOLENVSUSTAIN          GenType = C.GEN_VOLENVSUSTAIN
+	GEN_VOLENVRELEASE          GenType = C.GEN_VOLENVRELEASE
+	GEN_KEYTOVOLENVHOLD        GenType = C.GEN_KEYTOVOLENVHOLD
+	GEN_KEYTOVOLENVDECAY       GenType = C.GEN_KEYTOVOLENVDECAY
+	GEN_INSTRUMENT             GenType = C.GEN_INSTRUMENT
+	GEN_RESERVED1              GenType = C.GEN_RESERVED1
+	GEN_KEYRANGE               GenType = C.GEN_KEYRANGE
+	GEN_VELRANGE               GenType = C.GEN_VELRANGE
+	GEN_STARTLOOPADDRCOARSEOFS GenType = C.GEN_STARTLOOPADDRCOARSEOFS
+	GEN_KEYNUM                 GenType = C.GEN_KEYNUM
+	GEN_VELOCITY               GenType = C.GEN_VELOCITY
+	GEN_ATTENUATION            GenType = C.GEN_ATTENUATION
+	GEN_RESERVED2              GenType = C.GEN_RESERVED2
+	GEN_ENDLOOPADDRCOARSEOFS   GenType = C.GEN_ENDLOOPADDRCOARSEOFS
+	GEN_COARSETUNE             GenType = C.GEN_COARSETUNE
+	GEN_FINETUNE               GenType = C.GEN_FINETUNE
+	GEN_SAMPLEID               GenType = C.GEN_SAMPLEID
+	GEN_SAMPLEMODE             GenType = C.GEN_SAMPLEMODE
+	GEN_RESERVED3              GenType = C.GEN_RESERVED3
+	GEN_SCALETUNE              GenType = C.GEN_SCALETUNE
+	GEN_EXCLUSIVECLASS         GenType = C.GEN_EXCLUSIVECLASS
+	GEN_OVERRIDEROOTKEY        GenType = C.GEN_OVERRIDEROOTKEY
+	GEN_PITCH                  GenType = C.GEN_PITCH
+)
+
+// SetGen sets a generator offset on a channel. The value is added to the SoundFont's value
+// and applies to sounding voices on the channel as well as new ones.
+func (s *Synth) SetGen(channel uint8, param GenType, value float64) error {
+	return fluidStatus(C.fluid_synth_set_gen(s.ptr, C.int(channel), C.int(param), C.float(value)))
+}
+
+// GetGen returns the generator offset set on a channel
+func (s *Synth) GetGen(channel uint8, param GenType) float64 {
+	return float64(C.fluid_synth_get_gen(s.ptr, C.int(channel), C.int(param)))
+}
+
+// genDefaults are the generator defaults from the SoundFont 2.01 specification, section 8.1.3.
+// Generators that are not listed default to 0.
+var genDefaults = map[GenType]float32{
+	GEN_FILTERFC:        13500,
+	GEN_MODLFODELAY:     -12000,
+	GEN_VIBLFODELAY:     -12000,
+	GEN_MODENVDELAY:     -12000,
+	GEN_MODENVATTACK:    -12000,
+	GEN_MODENVHOLD:      -12000,
+	GEN_MODENVDECAY:     -12000,
+	GEN_MODENVRELEASE:   -12000,
+	GEN_VOLENVDELAY:     -12000,
+	GEN_VOLENVATTACK:    -12000,
+	GEN_VOLENVHOLD:      -12000,
+	GEN_VOLENVDECAY:     -12000,
+	GEN_VOLENVRELEASE:   -12000,
+	GEN_KEYNUM:          -1,
+	GEN_VELOCITY:        -1,
+	GEN_SCALETUNE:       100,
+	GEN_OVERRIDEROOTKEY: -1,
+}
+
+// GetGenDefault returns the default value of a generator as given by the SoundFont 2
+// specification, in the generator's unit (e.g. cents or timecents). The defaults don't depend on
+// a synth. The key and velocity ranges are stored as ranges rather than values and return 0.
+func GetGenDefault(param GenType) (float32, error) {
+	if param < 0 || param > GEN_PITCH {
+		return 0, fmt.Errorf("unknown generator: %d", param)
+	}
+	return genDefaults[param], nil
+}
@@ -0,0 +1,39 @@
+package fluidsynth2
+
+import "testing"
+
+func TestGetGenDefault(t *testing.T) {
+	tests := []struct {
+		gen  GenType
+		want float32
+	}{
+		{GEN_STARTADDROFS, 0},
+		{GEN_FILTERFC, 13500},
+		{GEN_FILTERQ, 0},
+		{GEN_MODLFODELAY, -12000},
+		{GEN_VIBLFOFREQ, 0},
+		{GEN_MODENVSUSTAIN, 0},
+		{GEN_VOLENVATTACK, -12000},
+		{GEN_VOLENVRELEASE, -12000},
+		{GEN_KEYRANGE, 0},
+		{GEN_KEYNUM, -1},
+		{GEN_VELOCITY, -1},
+		{GEN_ATTENUATION, 0},
+		{GEN_COARSETUNE, 0},
+		{GEN_SCALETUNE, 100},
+		{GEN_OVERRIDEROOTKEY, -1},
+	}
+	for _, tt := range tests {
+		got, err := GetGenDefault(tt.gen)
+		if err != nil {
+			t.Errorf("GetGenDefault(%d): %v", tt.gen, err)
+		} else if got != tt.want {
+			t.Errorf("GetGenDefault(%d) = %f, want %f", tt.gen, got, tt.want)
+		}
+	}
+	for _, gen := range []GenType{-1, GEN_PITCH + 1} {
+		if _, err := GetGenDefault(gen); err == nil {
+			t.Errorf("GetGenDefault(%d) accepted an unknown generator", gen)
+		}
+	}
+}
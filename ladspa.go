@@ -0,0 +1,69 @@
+//go:build ladspa
+
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// LADSPAFx controls the LADSPA effects chain of a synth.
+// It is only available when building with the "ladspa" tag against a fluidsynth
+// compiled with LADSPA support, and "synth.ladspa.active" must be enabled in the settings.
+type LADSPAFx struct {
+	ptr *C.fluid_ladspa_fx_t
+}
+
+// GetLADSPAFx returns the LADSPA effects chain of the synth
+func (s *Synth) GetLADSPAFx() (*LADSPAFx, error) {
+	fx := C.fluid_synth_get_ladspa_fx(s.ptr)
+	if fx == nil {
+		return nil, fmt.Errorf("LADSPA is not active, enable synth.ladspa.active")
+	}
+	return &LADSPAFx{fx}, nil
+}
+
+// IsActive returns true if the effects chain is processing audio
+func (l *LADSPAFx) IsActive() bool {
+	return C.fluid_ladspa_is_active(l.ptr) != 0
+}
+
+// Activate starts or stops processing audio through the effects chain
+func (l *LADSPAFx) Activate(on bool) error {
+	if on {
+		return fluidStatus(C.fluid_ladspa_activate(l.ptr))
+	}
+	return fluidStatus(C.fluid_ladspa_deactivate(l.ptr))
+}
+
+// Reset removes all effects and buffers from the chain. The chain must not be active.
+func (l *LADSPAFx) Reset() error {
+	return fluidStatus(C.fluid_ladspa_reset(l.ptr))
+}
+
+// AddEffect instantiates a plugin from a LADSPA library and adds it to the chain under the given name
+func (l *LADSPAFx) AddEffect(name, libPath, pluginLabel string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	clib := C.CString(libPath)
+	defer C.free(unsafe.Pointer(clib))
+	clabel := C.CString(pluginLabel)
+	defer C.free(unsafe.Pointer(clabel))
+	if C.fluid_ladspa_add_effect(l.ptr, cname, clib, clabel) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to add LADSPA effect %s (%s from %s)", name, pluginLabel, libPath)
+	}
+	return nil
+}
+
+// Check validates the effects chain and returns the reason if it can't be activated
+func (l *LADSPAFx) Check() error {
+	var buf [512]C.char
+	if C.fluid_ladspa_check(l.ptr, &buf[0], C.int(len(buf))) == C.FLUID_FAILED {
+		return fmt.Errorf("invalid LADSPA setup: %s", C.GoString(&buf[0]))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build !ladspa
+
+package fluidsynth2
+
+import "fmt"
+
+var errNoLADSPA = fmt.Errorf("LADSPA support is not available, build with the ladspa tag")
+
+// LADSPAFx controls the LADSPA effects chain of a synth.
+// This build has no LADSPA support, all methods return an error.
+type LADSPAFx struct{}
+
+// GetLADSPAFx returns the LADSPA effects chain of the synth
+func (s *Synth) GetLADSPAFx() (*LADSPAFx, error) {
+	return nil, errNoLADSPA
+}
+
+func (l *LADSPAFx) IsActive() bool {
+	return false
+}
+
+func (l *LADSPAFx) Activate(on bool) error {
+	return errNoLADSPA
+}
+
+func (l *LADSPAFx) Reset() error {
+	return errNoLADSPA
+}
+
+func (l *LADSPAFx) AddEffect(name, libPath, pluginLabel string) error {
+	return errNoLADSPA
+}
+
+func (l *LADSPAFx) Check() error {
+	return errNoLADSPA
+}
@@ -0,0 +1,37 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "sync"
+
+var (
+	lastError      string
+	lastErrorMu    sync.Mutex
+	logFunctionSet sync.Once
+)
+
+// installLogFunction starts recording the errors fluidsynth logs. It is called when the first
+// synth is created, before that LastError is always empty.
+func installLogFunction() {
+	logFunctionSet.Do(setGoLogFunction)
+}
+
+//export goLogError
+func goLogError(level C.int, message *C.char) {
+	msg := C.GoString(message)
+	lastErrorMu.Lock()
+	lastError = msg
+	lastErrorMu.Unlock()
+}
+
+// LastError returns the last error message logged by fluidsynth, e.g. why a soundfont failed to
+// load, or "" if there was none. fluid_synth_error was removed in fluidsynth 2, so the binding
+// records fluidsynth's log instead. The log is shared by all synths and isn't cleared, so the
+// message may come from another synth or an earlier failure.
+func (s *Synth) LastError() string {
+	lastErrorMu.Lock()
+	defer lastErrorMu.Unlock()
+	return lastError
+}
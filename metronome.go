@@ -0,0 +1,80 @@
+package fluidsynth2
+
+import (
+	"fmt"
+	"math"
+)
+
+// metronomeClickSeconds is the length of a metronome click
+const metronomeClickSeconds = 0.05
+
+// metronome schedules one click per beat. Every click is scheduled by a timer event for the
+// metronome's own client, whose callback then schedules the timer for the next beat.
+type metronome struct {
+	seq      *Sequencer
+	id, dest SeqClientID
+	click    SeqEvent
+	interval float64
+	next     float64
+}
+
+func (m *metronome) tick(tick uint, t SeqEventType) {
+	if t != SEQ_TIMER {
+		return
+	}
+	m.seq.SendAt(m.click, tick, true)
+	// the beat position is kept as a float so that rounding doesn't make the tempo drift
+	m.next += m.interval
+	m.seq.SendAt(SeqEvent{Type: SEQ_TIMER, Dest: m.id}, uint(math.Round(m.next)), true)
+}
+
+// StartMetronome plays a click on a channel of synth on every beat at bpm, until
+// StopMetronome is called. A running metronome is replaced.
+func (s *Sequencer) StartMetronome(synth *Synth, channel, note, velocity uint8, bpm float64) error {
+	if math.IsNaN(bpm) || bpm <= 0 {
+		return fmt.Errorf("invalid tempo: %f", bpm)
+	}
+	s.StopMetronome()
+	dest, err := s.RegisterSynth(synth)
+	if err != nil {
+		return err
+	}
+	scale := s.GetTimeScale()
+	m := &metronome{
+		seq:  s,
+		dest: dest,
+		click: SeqEvent{
+			Type:     SEQ_NOTE,
+			Dest:     dest,
+			Channel:  channel,
+			Key:      note,
+			Velocity: velocity,
+			Duration: uint(scale * metronomeClickSeconds),
+		},
+		interval: scale * 60 / bpm,
+		next:     float64(s.GetTick()),
+	}
+	if m.id, err = s.RegisterClient("metronome", m.tick); err != nil {
+		return err
+	}
+	s.state.mu.Lock()
+	s.state.metronome = m
+	s.state.mu.Unlock()
+	if err := s.SendAt(SeqEvent{Type: SEQ_TIMER, Dest: m.id}, uint(m.next), true); err != nil {
+		s.StopMetronome()
+		return err
+	}
+	return nil
+}
+
+// StopMetronome stops the metronome started with StartMetronome. Clicks that are already
+// sounding play to the end.
+func (s *Sequencer) StopMetronome() {
+	s.state.mu.Lock()
+	m := s.state.metronome
+	s.state.metronome = nil
+	s.state.mu.Unlock()
+	if m != nil {
+		s.UnregisterClient(m.id)
+	}
+}
@@ -0,0 +1,109 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "fmt"
+
+// MIDIEvent is a MIDI event owned by fluidsynth, as passed to the player's playback callback.
+// It is only valid for the duration of the callback.
+type MIDIEvent struct {
+	ptr *C.fluid_midi_event_t
+}
+
+func (e MIDIEvent) Type() EventType {
+	return EventType(C.fluid_midi_event_get_type(e.ptr))
+}
+
+// IsChannelMessage returns true for note, controller, program, pressure and pitch bend events
+func (e MIDIEvent) IsChannelMessage() bool {
+	t := e.Type()
+	return t >= NOTE_OFF && t <= PITCH_BEND
+}
+
+func (e MIDIEvent) Channel() int {
+	return int(C.fluid_midi_event_get_channel(e.ptr))
+}
+
+func (e MIDIEvent) SetChannel(channel int) {
+	C.fluid_midi_event_set_channel(e.ptr, C.int(channel))
+}
+
+func (e MIDIEvent) Key() int {
+	return int(C.fluid_midi_event_get_key(e.ptr))
+}
+
+func (e MIDIEvent) SetKey(key int) {
+	C.fluid_midi_event_set_key(e.ptr, C.int(key))
+}
+
+func (e MIDIEvent) Velocity() int {
+	return int(C.fluid_midi_event_get_velocity(e.ptr))
+}
+
+func (e MIDIEvent) SetVelocity(velocity int) {
+	C.fluid_midi_event_set_velocity(e.ptr, C.int(velocity))
+}
+
+func (e MIDIEvent) Control() int {
+	return int(C.fluid_midi_event_get_control(e.ptr))
+}
+
+func (e MIDIEvent) SetControl(control int) {
+	C.fluid_midi_event_set_control(e.ptr, C.int(control))
+}
+
+func (e MIDIEvent) Value() int {
+	return int(C.fluid_midi_event_get_value(e.ptr))
+}
+
+func (e MIDIEvent) SetValue(value int) {
+	C.fluid_midi_event_set_value(e.ptr, C.int(value))
+}
+
+func (e MIDIEvent) Program() int {
+	return int(C.fluid_midi_event_get_program(e.ptr))
+}
+
+func (e MIDIEvent) SetProgram(program int) {
+	C.fluid_midi_event_set_program(e.ptr, C.int(program))
+}
+
+func (e MIDIEvent) Pitch() int {
+	return int(C.fluid_midi_event_get_pitch(e.ptr))
+}
+
+func (e MIDIEvent) SetPitch(pitch int) {
+	C.fluid_midi_event_set_pitch(e.ptr, C.int(pitch))
+}
+
+// Pressure returns the pressure of key and channel pressure events. fluidsynth keeps the
+// channel pressure in the field used for the program.
+func (e MIDIEvent) Pressure() int {
+	if e.Type() == CHANNEL_PRESSURE {
+		return e.Program()
+	}
+	return e.Value()
+}
+
+// String formats the event for logging, e.g. "NoteOn ch=0 key=60 vel=100"
+func (e MIDIEvent) String() string {
+	t := e.Type()
+	switch t {
+	case NOTE_OFF, NOTE_ON:
+		return fmt.Sprintf("%s ch=%d key=%d vel=%d", t, e.Channel(), e.Key(), e.Velocity())
+	case KEY_PRESSURE:
+		return fmt.Sprintf("%s ch=%d key=%d value=%d", t, e.Channel(), e.Key(), e.Pressure())
+	case CONTROL_CHANGE:
+		return fmt.Sprintf("%s ch=%d ctrl=%d value=%d", t, e.Channel(), e.Control(), e.Value())
+	case PROGRAM_CHANGE:
+		return fmt.Sprintf("%s ch=%d program=%d", t, e.Channel(), e.Program())
+	case CHANNEL_PRESSURE:
+		return fmt.Sprintf("%s ch=%d value=%d", t, e.Channel(), e.Pressure())
+	case PITCH_BEND:
+		return fmt.Sprintf("%s ch=%d value=%d", t, e.Channel(), e.Pitch())
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,158 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"errors"
+	"fmt"
+)
+
+// Modulator source flags, combine one of each group with |
+const (
+	MOD_POSITIVE = C.FLUID_MOD_POSITIVE
+	MOD_NEGATIVE = C.FLUID_MOD_NEGATIVE
+	MOD_UNIPOLAR = C.FLUID_MOD_UNIPOLAR
+	MOD_BIPOLAR  = C.FLUID_MOD_BIPOLAR
+	MOD_LINEAR   = C.FLUID_MOD_LINEAR
+	MOD_CONCAVE  = C.FLUID_MOD_CONCAVE
+	MOD_CONVEX   = C.FLUID_MOD_CONVEX
+	MOD_SWITCH   = C.FLUID_MOD_SWITCH
+	MOD_GC       = C.FLUID_MOD_GC // source is a general controller below
+	MOD_CC       = C.FLUID_MOD_CC // source is a MIDI CC number
+)
+
+// General controller modulator sources, used with MOD_GC
+const (
+	MOD_NONE            = C.FLUID_MOD_NONE
+	MOD_VELOCITY        = C.FLUID_MOD_VELOCITY
+	MOD_KEY             = C.FLUID_MOD_KEY
+	MOD_KEYPRESSURE     = C.FLUID_MOD_KEYPRESSURE
+	MOD_CHANNELPRESSURE = C.FLUID_MOD_CHANNELPRESSURE
+	MOD_PITCHWHEEL      = C.FLUID_MOD_PITCHWHEEL
+	MOD_PITCHWHEELSENS  = C.FLUID_MOD_PITCHWHEELSENS
+)
+
+type ModMode int
+
+const (
+	// MOD_MODE_OVERWRITE replaces the amount of an identical default modulator, if there is one
+	MOD_MODE_OVERWRITE ModMode = C.FLUID_SYNTH_OVERWRITE
+	// MOD_MODE_ADD adds to the amount of an identical default modulator, if there is one
+	MOD_MODE_ADD ModMode = C.FLUID_SYNTH_ADD
+)
+
+// Modulator routes a source, such as a CC, to a generator (GEN_*) with a given amount
+type Modulator struct {
+	ptr *C.fluid_mod_t
+}
+
+func NewModulator() (*Modulator, error) {
+	mod := C.new_fluid_mod()
+	if mod == nil {
+		return nil, fmt.Errorf("failed to create modulator")
+	}
+	return &Modulator{mod}, nil
+}
+
+func (m *Modulator) Delete() {
+	if m.ptr != nil {
+		C.delete_fluid_mod(m.ptr)
+		m.ptr = nil
+	}
+}
+
+// SetSource1 sets the primary source, e.g. (74, MOD_CC|MOD_UNIPOLAR|MOD_POSITIVE) for CC 74
+func (m *Modulator) SetSource1(src, flags int) {
+	C.fluid_mod_set_source1(m.ptr, C.int(src), C.int(flags))
+}
+
+// SetSource2 sets the secondary source that scales the primary one, MOD_NONE for none
+func (m *Modulator) SetSource2(src, flags int) {
+	C.fluid_mod_set_source2(m.ptr, C.int(src), C.int(flags))
+}
+
+// SetDest sets the generator (GEN_*) the modulator controls
+func (m *Modulator) SetDest(gen GenType) {
+	C.fluid_mod_set_dest(m.ptr, C.int(gen))
+}
+
+// SetAmount sets the modulation depth, in the unit of the destination generator
+func (m *Modulator) SetAmount(amount float64) {
+	C.fluid_mod_set_amount(m.ptr, C.double(amount))
+}
+
+func (m *Modulator) Source1() (src, flags int) {
+	return int(C.fluid_mod_get_source1(m.ptr)), int(C.fluid_mod_get_flags1(m.ptr))
+}
+
+func (m *Modulator) Source2() (src, flags int) {
+	return int(C.fluid_mod_get_source2(m.ptr)), int(C.fluid_mod_get_flags2(m.ptr))
+}
+
+func (m *Modulator) Dest() GenType {
+	return GenType(C.fluid_mod_get_dest(m.ptr))
+}
+
+func (m *Modulator) Amount() float64 {
+	return float64(C.fluid_mod_get_amount(m.ptr))
+}
+
+// modKey identifies a modulator the way fluidsynth compares them, everything but the amount
+type modKey struct {
+	src1, flags1, src2, flags2 int
+	dest                       GenType
+}
+
+func (m *Modulator) key() modKey {
+	src1, flags1 := m.Source1()
+	src2, flags2 := m.Source2()
+	return modKey{src1, flags1, src2, flags2, m.Dest()}
+}
+
+// AddDefaultMod adds a modulator to the default modulators, which apply to every voice started
+// afterwards in addition to the soundfont's own modulators. The synth keeps a copy, so mod
+// can be deleted afterwards.
+func (s *Synth) AddDefaultMod(mod *Modulator, mode ModMode) error {
+	if mod == nil || mod.ptr == nil {
+		return fmt.Errorf("invalid modulator")
+	}
+	if C.fluid_synth_add_default_mod(s.ptr, mod.ptr, C.int(mode)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to add default modulator")
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.defaultMods == nil {
+		s.state.defaultMods = make(map[modKey]bool)
+	}
+	s.state.defaultMods[mod.key()] = true
+	return nil
+}
+
+// ResetDefaultMods removes all modulators added with AddDefaultMod. Modulators that are
+// identical to one of fluidsynth's built-in defaults are removed as well, as fluidsynth
+// can't tell them apart.
+func (s *Synth) ResetDefaultMods() error {
+	s.state.mu.Lock()
+	keys := s.state.defaultMods
+	s.state.defaultMods = nil
+	s.state.mu.Unlock()
+
+	var errs []error
+	for k := range keys {
+		mod, err := NewModulator()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		mod.SetSource1(k.src1, k.flags1)
+		mod.SetSource2(k.src2, k.flags2)
+		mod.SetDest(k.dest)
+		if C.fluid_synth_remove_default_mod(s.ptr, mod.ptr) == C.FLUID_FAILED {
+			errs = append(errs, fmt.Errorf("failed to remove default modulator: source=%d, dest=%d", k.src1, k.dest))
+		}
+		mod.Delete()
+	}
+	return errors.Join(errs...)
+}
@@ -5,30 +5,306 @@ package fluidsynth2
 // #include <stdlib.h>
 import "C"
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
 	"unsafe"
 )
 
 type Player struct {
-	ptr  *C.fluid_player_t
-	open bool
+	ptr   *C.fluid_player_t
+	open  bool
+	state *playerState
 }
 
+// playerState holds binding-side state that is shared by all copies of a Player
+// and used from the player's callbacks.
+type playerState struct {
+	mu       sync.Mutex
+	handle   unsafe.Pointer
+	player   *C.fluid_player_t
+	synth    *C.fluid_synth_t
+	playlist []string
+
+	// ticking is set if the Go tick callback is installed, which is needed to track the file
+	// that is playing, for loop regions and for the tempo change callback
+	ticking  bool
+	current  int
+	lastTick int
+	seeking  bool
+
+	// synthState is the state of the player's synth, to check that it is still open
+	synthState *synthState
+
+	// playback is set once the Go playback callback replaced fluidsynth's default one
+	playback      bool
+	channelOffset int
+	channelMask   uint16
+	bankOverride  map[int]int
+
+	tempo         int
+	onTempoChange func(bpm float64)
+
+	closed bool
+	done   chan struct{}
+
+	// fade counts the fades started, so that a running fade stops when a new one starts
+	fade int
+
+	// loopStart and loopEnd are the loop region in ticks, loopEnd is 0 without a region
+	loopStart int
+	loopEnd   int
+}
+
+// errNoTickCallback is returned by the features that depend on the player's tick callback if it
+// couldn't be installed, i.e. with fluidsynth 2.1 and older
+var errNoTickCallback = fmt.Errorf("player has no tick callback, fluidsynth 2.2 or newer is needed")
+
 func NewPlayer(synth Synth) Player {
-	return Player{
-		ptr:  C.new_fluid_player(synth.ptr),
-		open: true,
+	p := Player{
+		ptr:   C.new_fluid_player(synth.ptr),
+		open:  true,
+		state: &playerState{synth: synth.ptr, synthState: synth.state, channelMask: allChannels},
 	}
+	p.state.player = p.ptr
+	p.state.handle = newCallbackHandle(p.state)
+	p.state.ticking = setGoTickCallback(p.ptr, p.state.handle) == nil
+	synth.state.addPlayer(p.state)
+	return p
 }
 
-// Close deletes the fluid player
+// Close deletes the fluid player. A player has to be deleted before its synth, as deleting it
+// unregisters it from the synth; Synth.Close closes the players that are still open, after
+// which Close does nothing.
 func (p *Player) Close() {
 	if p.open {
-		C.delete_fluid_player(p.ptr)
+		p.state.close()
 		p.open = false
 	}
 }
 
+// close deletes the player once. The player's lock isn't held while deleting it: fluidsynth
+// waits for a running player callback, which takes the lock itself.
+func (st *playerState) close() {
+	st.mu.Lock()
+	closed := st.closed
+	st.closed = true
+	st.mu.Unlock()
+	if closed {
+		return
+	}
+	st.synthState.removePlayer(st)
+	C.delete_fluid_player(st.player)
+	deleteCallbackHandle(st.handle)
+}
+
+// goPlayerTick is called by fluidsynth every time the player has processed its events.
+// The tick counter restarts when the player moves on to the next file of the playlist,
+// which is used to keep track of the file that is playing.
+//
+//export goPlayerTick
+func goPlayerTick(data unsafe.Pointer, tick C.int) C.int {
+	st := callbackValue(data).(*playerState)
+	st.mu.Lock()
+	if int(tick) < st.lastTick {
+		if st.seeking {
+			st.seeking = false
+		} else if len(st.playlist) > 0 {
+			st.current = (st.current + 1) % len(st.playlist)
+		}
+	}
+	st.lastTick = int(tick)
+
+	// fluid_player_seek only takes effect on the player's next callback, so the seek may be
+	// requested again until then, which is harmless
+	if st.loopEnd > 0 && int(tick) >= st.loopEnd {
+		if C.fluid_player_seek(st.player, C.int(st.loopStart)) == C.FLUID_OK {
+			st.seeking = true
+		}
+	}
+
+	// fluidsynth handles set-tempo events itself without passing them to the playback
+	// callback, so tempo changes are noticed by comparing the tempo on every tick
+	var onTempoChange func(bpm float64)
+	tempo := int(C.fluid_player_get_midi_tempo(st.player))
+	if tempo > 0 && tempo != st.tempo {
+		st.tempo = tempo
+		onTempoChange = st.onTempoChange
+	}
+	st.mu.Unlock()
+
+	if onTempoChange != nil {
+		onTempoChange(60e6 / float64(tempo))
+	}
+	return C.FLUID_OK
+}
+
+// SetTempoChangeCallback sets a func that is called with the tempo when playback starts and
+// whenever it changes, nil removes it. It is called from fluidsynth's player thread right after
+// the set-tempo event has been processed, and only sees tempo changes of the MIDI file while
+// the player uses TEMPO_INTERNAL. Tempo changes made with SetTempo are reported as well.
+func (p *Player) SetTempoChangeCallback(fn func(bpm float64)) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	if !p.state.ticking {
+		return errNoTickCallback
+	}
+	p.state.onTempoChange = fn
+	return nil
+}
+
+// installPlayback routes the player's events through goPlayerPlayback, so that they can be
+// rewritten before they reach the synth. It is only done once the first feature needing it is used.
+func (p *Player) installPlayback() error {
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	if p.state.playback {
+		return nil
+	}
+	if err := setGoPlaybackCallback(p.ptr, p.state.handle); err != nil {
+		return fmt.Errorf("failed to set playback callback")
+	}
+	p.state.playback = true
+	return nil
+}
+
+//export goPlayerPlayback
+func goPlayerPlayback(data unsafe.Pointer, event *C.fluid_midi_event_t) C.int {
+	st := callbackValue(data).(*playerState)
+	ev := MIDIEvent{event}
+	st.mu.Lock()
+	offset := st.channelOffset
+	mask := st.channelMask
+	var bank int
+	var overridden bool
+	if ev.Type() == CONTROL_CHANGE && ev.Control() == CC_BANK_SELECT {
+		bank, overridden = st.bankOverride[ev.Value()]
+	}
+	st.mu.Unlock()
+
+	// Note-offs always pass, so that notes started before a channel was muted are released
+	if t := ev.Type(); t == NOTE_ON || t == KEY_PRESSURE {
+		if mask&(1<<ev.Channel()) == 0 {
+			return C.FLUID_OK
+		}
+	}
+
+	// The event belongs to the loaded MIDI file and is played again when looping,
+	// so any change is undone once the synth has handled it.
+	if overridden {
+		value := ev.Value()
+		ev.SetValue(bank)
+		defer ev.SetValue(value)
+	}
+	if offset != 0 && ev.IsChannelMessage() {
+		channel := ev.Channel()
+		ev.SetChannel(channel + offset)
+		defer ev.SetChannel(channel)
+	}
+	return C.fluid_synth_handle_midi_event(unsafe.Pointer(st.synth), event)
+}
+
+// SetLoopRegion makes the player jump back to startTick whenever it reaches endTick. The region
+// takes precedence over SetLoop: the end of the file is never reached while the region is set,
+// unless endTick lies beyond it. Jumps happen on the player's callbacks, so playback can run
+// a few milliseconds past endTick.
+func (p *Player) SetLoopRegion(startTick, endTick int) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if startTick < 0 || endTick <= startTick {
+		return fmt.Errorf("invalid loop region: %d-%d", startTick, endTick)
+	}
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	if !p.state.ticking {
+		return errNoTickCallback
+	}
+	p.state.loopStart = startTick
+	p.state.loopEnd = endTick
+	return nil
+}
+
+// ClearLoopRegion removes the loop region set with SetLoopRegion
+func (p *Player) ClearLoopRegion() {
+	p.state.mu.Lock()
+	p.state.loopStart = 0
+	p.state.loopEnd = 0
+	p.state.mu.Unlock()
+}
+
+// SetChannelOffset shifts the channel of every event played by the player by offset, e.g. to
+// layer two MIDI files on different channels of a synth. The synth needs enough channels for
+// the shifted events ("synth.midi-channels", 16 by default); events shifted past the last
+// channel are dropped by fluidsynth.
+func (p *Player) SetChannelOffset(offset int) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if offset < 0 {
+		return fmt.Errorf("invalid channel offset: %d", offset)
+	}
+	if err := p.installPlayback(); err != nil {
+		return err
+	}
+	p.state.mu.Lock()
+	p.state.channelOffset = offset
+	p.state.mu.Unlock()
+	return nil
+}
+
+// allChannels is the channel mask with every channel of a MIDI file enabled
+const allChannels = 0xffff
+
+// SetChannelMask mutes channels of the MIDI file: bit n of mask enables channel n. The notes of
+// muted channels are dropped, while controller, program and pitch bend events still reach the
+// synth, so a channel sounds as intended when it is enabled again. The mask applies to the
+// channels of the file, before SetChannelOffset shifts them.
+func (p *Player) SetChannelMask(mask uint16) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if err := p.installPlayback(); err != nil {
+		return err
+	}
+	p.state.mu.Lock()
+	p.state.channelMask = mask
+	p.state.mu.Unlock()
+	return nil
+}
+
+// SetBankOverride replaces the bank select (CC 0) value fromBank with toBank in the events
+// played by the player, e.g. to map a file's banks onto a soundfont with a different layout.
+// Setting toBank to fromBank removes the override. Only the bank select MSB is rewritten, which
+// is the whole bank number with the default "synth.midi-bank-select" of "gs". The rewritten bank
+// select stays in its place in the file, so it still takes effect with the next program change.
+func (p *Player) SetBankOverride(fromBank, toBank int) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if fromBank < 0 || fromBank > MAX_MIDI_CC_VALUE || toBank < 0 || toBank > MAX_MIDI_CC_VALUE {
+		return fmt.Errorf("invalid bank override: %d->%d", fromBank, toBank)
+	}
+	if err := p.installPlayback(); err != nil {
+		return err
+	}
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	if fromBank == toBank {
+		delete(p.state.bankOverride, fromBank)
+		return nil
+	}
+	if p.state.bankOverride == nil {
+		p.state.bankOverride = make(map[int]int)
+	}
+	p.state.bankOverride[fromBank] = toBank
+	return nil
+}
+
 // Add plays files from disk
 func (p *Player) Add(filename string) error {
 	if !p.open {
@@ -39,6 +315,9 @@ func (p *Player) Add(filename string) error {
 	if status := C.fluid_player_add(p.ptr, cpath); status == C.FLUID_FAILED {
 		return fmt.Errorf("failed to add file to player: %s", filename)
 	}
+	p.state.mu.Lock()
+	p.state.playlist = append(p.state.playlist, filename)
+	p.state.mu.Unlock()
 	return nil
 }
 
@@ -52,7 +331,53 @@ func (p *Player) AddMem(data []byte) error {
 	}
 	cb := C.CBytes(data)
 	defer C.free(unsafe.Pointer(cb))
-	return fluidStatus(C.fluid_player_add_mem(p.ptr, cb, C.size_t(len(data))))
+	if err := fluidStatus(C.fluid_player_add_mem(p.ptr, cb, C.size_t(len(data)))); err != nil {
+		return err
+	}
+	p.state.mu.Lock()
+	p.state.playlist = append(p.state.playlist, memoryFilename)
+	p.state.mu.Unlock()
+	return nil
+}
+
+// memoryFilename is the playlist entry for MIDI data added with AddMem
+const memoryFilename = "<memory>"
+
+// Playlist returns the files added to the player, in playback order.
+// Data added with AddMem is listed as "<memory>".
+func (p *Player) Playlist() []string {
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	return append([]string(nil), p.state.playlist...)
+}
+
+// GetCurrentIndex returns the playlist index of the file that is playing
+func (p *Player) GetCurrentIndex() (int, error) {
+	if !p.open {
+		return 0, fmt.Errorf("player is closed")
+	}
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	if !p.state.ticking {
+		return 0, errNoTickCallback
+	}
+	if len(p.state.playlist) == 0 {
+		return 0, fmt.Errorf("playlist is empty")
+	}
+	return p.state.current, nil
+}
+
+// GetCurrentFilename returns the name of the file that is playing.
+// fluidsynth doesn't report the playlist position, so the binding tracks it by watching the
+// player's tick counter restart when the next file starts.
+func (p *Player) GetCurrentFilename() (string, error) {
+	i, err := p.GetCurrentIndex()
+	if err != nil {
+		return "", err
+	}
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	return p.state.playlist[i], nil
 }
 
 func (p *Player) Play() error {
@@ -69,7 +394,89 @@ func (p *Player) SetLoop(loops int) {
 }
 
 func (p *Player) Seek(ticks int) error {
-	return fluidStatus(C.fluid_player_seek(p.ptr, C.int(ticks)))
+	if err := fluidStatus(C.fluid_player_seek(p.ptr, C.int(ticks))); err != nil {
+		return err
+	}
+	// Seeking backwards also restarts the tick counter, which must not count as a file change
+	p.state.mu.Lock()
+	p.state.seeking = ticks < p.state.lastTick
+	p.state.mu.Unlock()
+	return nil
+}
+
+// Reset stops the player and rewinds it to the start of the playlist, so that Play starts over.
+// fluidsynth has no way to return a player to READY: the status reads DONE until Play is called.
+// The seek is only carried out by the player's next callback, i.e. once it plays again.
+func (p *Player) Reset() error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	p.Stop()
+	if err := fluidStatus(C.fluid_player_seek(p.ptr, 0)); err != nil {
+		return fmt.Errorf("failed to rewind player")
+	}
+	p.state.mu.Lock()
+	p.state.current = 0
+	p.state.lastTick = 0
+	p.state.seeking = false
+	p.state.mu.Unlock()
+	return nil
+}
+
+// GetDivision returns the number of ticks per quarter note of the MIDI file, or 0 before the
+// player has loaded it
+func (p *Player) GetDivision() (int, error) {
+	if !p.open {
+		return 0, fmt.Errorf("player is closed")
+	}
+	return playerDivision(p.ptr)
+}
+
+// ticksPerSecond converts the current tempo to ticks per second. fluidsynth doesn't expose the
+// tempo map, so the conversions between ticks and seconds assume the tempo doesn't change.
+func (p *Player) ticksPerSecond() (float64, error) {
+	division, err := p.GetDivision()
+	if err != nil {
+		return 0, err
+	}
+	tempo := p.GetTempo()
+	if division <= 0 || tempo <= 0 {
+		return 0, fmt.Errorf("no tempo information: division=%d, tempo=%d", division, tempo)
+	}
+	return float64(division) * 1e6 / float64(tempo), nil
+}
+
+// SeekSeconds seeks to a position given in seconds, see ticksPerSecond for the accuracy
+func (p *Player) SeekSeconds(sec float64) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if sec < 0 {
+		return fmt.Errorf("invalid position: %f", sec)
+	}
+	tps, err := p.ticksPerSecond()
+	if err != nil {
+		return err
+	}
+	return p.Seek(int(sec*tps + 0.5))
+}
+
+// GetCurrentSeconds returns the playback position in seconds
+func (p *Player) GetCurrentSeconds() (float64, error) {
+	tps, err := p.ticksPerSecond()
+	if err != nil {
+		return 0, err
+	}
+	return float64(p.GetCurrentTick()) / tps, nil
+}
+
+// GetTotalSeconds returns the length of the sequence in seconds
+func (p *Player) GetTotalSeconds() (float64, error) {
+	tps, err := p.ticksPerSecond()
+	if err != nil {
+		return 0, err
+	}
+	return float64(p.GetTotalTicks()) / tps, nil
 }
 
 // Join blocks until playback has finished
@@ -77,6 +484,176 @@ func (p *Player) Join() {
 	C.fluid_player_join(p.ptr)
 }
 
+// JoinContext blocks until playback has finished or ctx is done.
+// If ctx is done first the player is stopped and ctx.Err() is returned.
+// Unlike Join it polls the player's status, because fluid_player_join can't be interrupted and
+// never returns if nothing renders the synth.
+func (p *Player) JoinContext(ctx context.Context) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	t := time.NewTicker(donePollInterval)
+	defer t.Stop()
+	for {
+		status, ok := p.state.status()
+		if !ok {
+			return fmt.Errorf("player is closed")
+		}
+		if status == StatusDone {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			p.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// PlayBlocking starts the player and blocks until it is done or ctx is done,
+// in which case the player is stopped and ctx.Err() is returned
+func (p *Player) PlayBlocking(ctx context.Context) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if err := p.Play(); err != nil {
+		return fmt.Errorf("failed to start player")
+	}
+	return p.JoinContext(ctx)
+}
+
+// donePollInterval is how often the goroutine behind Done checks the player's status
+const donePollInterval = 10 * time.Millisecond
+
+// Done returns a channel that is closed once the player is DONE, i.e. it finished or was stopped.
+// The channel is created on the first call, together with a goroutine that polls the player's
+// status; the goroutine exits when the channel is closed or the player is closed. The channel is
+// closed only once, so it doesn't notice the player being played again after Reset.
+func (p *Player) Done() <-chan struct{} {
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	if p.state.done == nil {
+		p.state.done = make(chan struct{})
+		go p.pollDone(p.state.done)
+	}
+	return p.state.done
+}
+
+func (p *Player) pollDone(done chan struct{}) {
+	t := time.NewTicker(donePollInterval)
+	defer t.Stop()
+	for range t.C {
+		status, ok := p.state.status()
+		if !ok {
+			return
+		}
+		if status == StatusDone {
+			close(done)
+			return
+		}
+	}
+}
+
+// status returns the player's status, or false if the player is closed. The status is read
+// under the lock so that Close can't delete the player meanwhile.
+func (st *playerState) status() (PlayerStatus, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return StatusUnknown, false
+	}
+	return PlayerStatus(C.fluid_player_get_status(st.player)), true
+}
+
+// fadeStep is the interval at which Fade changes the gain
+const fadeStep = 10 * time.Millisecond
+
+// SetGain sets the gain of the synth the player plays on. A player has no volume of its own,
+// so this changes the volume of everything else played on the synth as well. It stops a
+// running Fade.
+func (p *Player) SetGain(g float32) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if g < 0 || g > maxGain {
+		return fmt.Errorf("invalid gain: %f", g)
+	}
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+	p.state.fade++
+	if !p.state.setSynthGain(g) {
+		return fmt.Errorf("synth is closed")
+	}
+	return nil
+}
+
+// synthGain returns the gain of the player's synth and false if the synth is closed, see setSynthGain
+func (st *playerState) synthGain() (float32, bool) {
+	st.synthState.mu.Lock()
+	defer st.synthState.mu.Unlock()
+	if st.synthState.closed {
+		return 0, false
+	}
+	return float32(C.fluid_synth_get_gain(st.synth)), true
+}
+
+// setSynthGain sets the gain of the player's synth unless the synth is closed, holding the
+// synth's lock so that it can't be deleted in between. Called with st.mu held.
+func (st *playerState) setSynthGain(g float32) bool {
+	st.synthState.mu.Lock()
+	defer st.synthState.mu.Unlock()
+	if st.synthState.closed {
+		return false
+	}
+	C.fluid_synth_set_gain(st.synth, C.float(g))
+	return true
+}
+
+// Fade ramps the synth's gain linearly from its current value to target over d, see SetGain.
+// It returns right away and changes the gain from a goroutine every 10ms. A new Fade or SetGain
+// call stops a running fade, as does closing the player or the synth.
+func (p *Player) Fade(target float32, d time.Duration) error {
+	if !p.open {
+		return fmt.Errorf("player is closed")
+	}
+	if target < 0 || target > maxGain {
+		return fmt.Errorf("invalid gain: %f", target)
+	}
+	if d <= 0 {
+		return p.SetGain(target)
+	}
+	p.state.mu.Lock()
+	p.state.fade++
+	fade := p.state.fade
+	start, ok := p.state.synthGain()
+	p.state.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("synth is closed")
+	}
+
+	go func() {
+		t := time.NewTicker(fadeStep)
+		defer t.Stop()
+		begin := time.Now()
+		for now := range t.C {
+			pos := float32(now.Sub(begin)) / float32(d)
+			p.state.mu.Lock()
+			if p.state.closed || p.state.fade != fade {
+				p.state.mu.Unlock()
+				return
+			}
+			g := start + (target-start)*min(pos, 1)
+			if !p.state.setSynthGain(g) || pos >= 1 {
+				p.state.mu.Unlock()
+				return
+			}
+			p.state.mu.Unlock()
+		}
+	}()
+	return nil
+}
+
 // GetBPM returns the beats per minute of the MIDI player
 func (p *Player) GetBPM() int {
 	return int(C.fluid_player_get_bpm(p.ptr))
@@ -103,8 +680,7 @@ func (p *Player) SetTempo(t TempoType, bpm float64) error {
 	if t < TEMPO_INTERNAL || t > TEMPO_EXTERNAL_MIDI {
 		return fmt.Errorf("invalid tempo type: %d", t)
 	}
-	C.fluid_player_set_tempo(p.ptr, C.int(t), C.double(bpm))
-	return nil
+	return setPlayerTempo(p.ptr, t, bpm)
 }
 
 // GetCurrentTick returns the number of tempo ticks passed
@@ -117,23 +693,71 @@ func (p *Player) GetTotalTicks() int {
 	return int(C.fluid_player_get_total_ticks(p.ptr))
 }
 
-// GetStatus returns the current status of the player
-func (p *Player) GetStatus() (string, error) {
+type PlayerStatus int
+
+const (
+	StatusReady    PlayerStatus = C.FLUID_PLAYER_READY
+	StatusPlaying  PlayerStatus = C.FLUID_PLAYER_PLAYING
+	StatusStopping PlayerStatus = C.FLUID_PLAYER_STOPPING
+	StatusDone     PlayerStatus = C.FLUID_PLAYER_DONE
+	StatusUnknown  PlayerStatus = -1
+)
+
+func (st PlayerStatus) String() string {
+	switch st {
+	case StatusReady:
+		return "READY"
+	case StatusPlaying:
+		return "PLAYING"
+	case StatusStopping:
+		return "STOPPING"
+	case StatusDone:
+		return "DONE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// GetStatusCode returns the current status of the player
+func (p *Player) GetStatusCode() (PlayerStatus, error) {
 	if !p.open {
-		return "", fmt.Errorf("player is closed")
+		return StatusUnknown, fmt.Errorf("player is closed")
 	}
-	status := int(C.fluid_player_get_status(p.ptr))
+	status := PlayerStatus(C.fluid_player_get_status(p.ptr))
 
-	//Codes documented here http://www.fluidsynth.org/api/midi_8h.html#a5ec93766f61465dedbbac9bdb76ced83
+	//Codes documented here https://www.fluidsynth.org/api/group__midi__player.html
 
 	switch status {
-	case 0:
-		return "READY", nil
-	case 1:
-		return "PLAYING", nil
-	case 2:
-		return "DONE", nil
+	case StatusReady, StatusPlaying, StatusStopping, StatusDone:
+		return status, nil
 	default:
-		return "UNKNOWN", fmt.Errorf("unknown status code: %d", status)
+		return StatusUnknown, fmt.Errorf("unknown status code: %d", status)
 	}
 }
+
+// GetStatus returns the current status of the player
+func (p *Player) GetStatus() (string, error) {
+	status, err := p.GetStatusCode()
+	if !p.open {
+		return "", err
+	}
+	return status.String(), err
+}
+
+// IsReady returns true if the player hasn't started playing yet
+func (p *Player) IsReady() (bool, error) {
+	status, err := p.GetStatusCode()
+	return status == StatusReady, err
+}
+
+// IsPlaying returns true while the player is playing
+func (p *Player) IsPlaying() (bool, error) {
+	status, err := p.GetStatusCode()
+	return status == StatusPlaying, err
+}
+
+// IsDone returns true once the player has finished or was stopped
+func (p *Player) IsDone() (bool, error) {
+	status, err := p.GetStatusCode()
+	return status == StatusDone, err
+}
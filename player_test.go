@@ -0,0 +1,89 @@
+package fluidsynth2
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFade(t *testing.T) {
+	synth := newTestSynth(t)
+	player := NewPlayer(synth)
+	defer player.Close()
+	if err := player.SetGain(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Fade(0.5, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if g := synth.GetGain(); math.Abs(float64(g)-0.5) > 1e-6 {
+		t.Errorf("gain after fade = %f, want 0.5", g)
+	}
+}
+
+func TestFadeSynthClosed(t *testing.T) {
+	settings := NewSettings()
+	defer settings.Close()
+	synth := NewSynth(settings)
+	// closing the synth closes the player first, Close is a no-op afterwards
+	player := NewPlayer(synth)
+	defer player.Close()
+	if err := player.Fade(0.5, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	synth.Close()
+	time.Sleep(100 * time.Millisecond)
+	if err := player.SetGain(0.2); err == nil {
+		t.Error("SetGain succeeded on a closed synth")
+	}
+	if err := player.Fade(0.2, time.Second); err == nil {
+		t.Error("Fade started on a closed synth")
+	}
+}
+
+func TestJoinContextCancel(t *testing.T) {
+	settings := NewSettings()
+	defer settings.Close()
+	settings.SetString("player.timing-source", "sample")
+	synth := NewSynth(settings)
+	defer synth.Close()
+	player := NewPlayer(synth)
+	defer player.Close()
+	if err := player.AddMem(testSMF(4)); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Play(); err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing renders the synth, so the player never finishes on its own
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := player.JoinContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("JoinContext = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("JoinContext returned after %s", d)
+	}
+}
+
+func TestSynthCloseClosesPlayers(t *testing.T) {
+	settings := NewSettings()
+	defer settings.Close()
+	synth := NewSynth(settings)
+	player := NewPlayer(synth)
+	closed := NewPlayer(synth)
+	closed.Close()
+	synth.Close()
+	if _, ok := player.state.status(); ok {
+		t.Error("player is still open after its synth was closed")
+	}
+	if n := len(synth.state.players); n != 0 {
+		t.Errorf("synth still tracks %d players", n)
+	}
+	player.Close()
+}
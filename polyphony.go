@@ -0,0 +1,84 @@
+package fluidsynth2
+
+import "fmt"
+
+// channelVoices is the state of the binding's per-channel voice manager
+type channelVoices struct {
+	max   int
+	notes []uint8 // held notes, oldest first
+}
+
+// SetChannelPolyphony limits the number of notes held on a channel at once, 0 removes the limit.
+// fluidsynth only limits polyphony globally, so the binding tracks the notes started with NoteOn
+// and, when a new note exceeds the limit, releases the oldest one with NoteOff first. It counts
+// held keys rather than voices: a note may use several voices, and notes that are released but
+// still sounding (or held by the sustain pedal) are not counted. Notes sent with SendEvents or
+// played by a Player bypass the limit, but note-offs from NoteOffVel and SendEvents are tracked.
+func (s *Synth) SetChannelPolyphony(channel uint8, max int) error {
+	if max < 0 {
+		return fmt.Errorf("invalid polyphony: %d", max)
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if max == 0 {
+		delete(s.state.channelVoices, channel)
+		return nil
+	}
+	if s.state.channelVoices == nil {
+		s.state.channelVoices = make(map[uint8]*channelVoices)
+	}
+	if cv, ok := s.state.channelVoices[channel]; ok {
+		cv.max = max
+	} else {
+		s.state.channelVoices[channel] = &channelVoices{max: max}
+	}
+	return nil
+}
+
+// startNote records a note started on a channel with a polyphony limit and returns the notes
+// that have to be released to stay within the limit
+func (s *Synth) startNote(channel, note uint8) []uint8 {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	cv, ok := s.state.channelVoices[channel]
+	if !ok {
+		return nil
+	}
+	cv.remove(note)
+	var steal []uint8
+	for len(cv.notes) >= cv.max {
+		steal = append(steal, cv.notes[0])
+		cv.notes = cv.notes[1:]
+	}
+	cv.notes = append(cv.notes, note)
+	return steal
+}
+
+// stopNote forgets a note released on a channel with a polyphony limit
+func (s *Synth) stopNote(channel, note uint8) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if cv, ok := s.state.channelVoices[channel]; ok {
+		cv.remove(note)
+	}
+}
+
+// clearNotes forgets the notes of a channel, -1 for all channels
+func (s *Synth) clearNotes(channel int) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	for ch, cv := range s.state.channelVoices {
+		if channel == -1 || int(ch) == channel {
+			cv.notes = nil
+		}
+	}
+}
+
+func (cv *channelVoices) remove(note uint8) {
+	for i, n := range cv.notes {
+		if n == note {
+			cv.notes = append(cv.notes[:i], cv.notes[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package fluidsynth2
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChannelPolyphony(t *testing.T) {
+	synth := newTestSynth(t)
+	if err := synth.SetChannelPolyphony(0, 2); err != nil {
+		t.Fatal(err)
+	}
+	held := func() []uint8 {
+		synth.state.mu.Lock()
+		defer synth.state.mu.Unlock()
+		return slices.Clone(synth.state.channelVoices[0].notes)
+	}
+	steps := []struct {
+		name string
+		do   func() error
+		want []uint8
+	}{
+		{"note on", func() error { return synth.NoteOn(0, 60, 100) }, []uint8{60}},
+		{"second note", func() error { return synth.NoteOn(0, 62, 100) }, []uint8{60, 62}},
+		{"third note steals", func() error { return synth.NoteOn(0, 64, 100) }, []uint8{62, 64}},
+		{"NoteOffVel", func() error { return synth.NoteOffVel(0, 64, 40) }, []uint8{62}},
+		{"SendEvents note off", func() error {
+			return synth.SendEvents([]RawMIDIEvent{{Type: NOTE_ON, P1: 65, P2: 100}, {Type: NOTE_OFF, P1: 62}})
+		}, nil},
+		{"note on velocity 0", func() error { return synth.NoteOn(0, 66, 0) }, nil},
+	}
+	for _, step := range steps {
+		if err := step.do(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := held(); !slices.Equal(got, step.want) {
+			t.Errorf("%s: held notes %v, want %v", step.name, got, step.want)
+		}
+	}
+}
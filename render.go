@@ -0,0 +1,316 @@
+package fluidsynth2
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RenderLoop renders audio from a synth in a goroutine and hands it to a sink,
+// for use with custom audio backends.
+type RenderLoop struct {
+	synth *Synth
+	mu    sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+	err   error
+}
+
+func NewRenderLoop(synth *Synth) *RenderLoop {
+	return &RenderLoop{synth: synth}
+}
+
+// Start renders bufFrames frames at a time and passes them to sink until Stop is called,
+// the synth is closed, or sink returns an error. The slices passed to sink are reused for
+// the next block, so sink must copy any data it wants to keep. sink is called from the
+// render goroutine and must not block indefinitely, otherwise Stop never returns.
+func (r *RenderLoop) Start(bufFrames int, sink func(left, right []int16) error) error {
+	if bufFrames <= 0 {
+		return fmt.Errorf("invalid buffer size: %d", bufFrames)
+	}
+	if sink == nil {
+		return fmt.Errorf("nil sink")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done != nil {
+		return fmt.Errorf("render loop is already running")
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	r.err = nil
+	go r.run(bufFrames, sink, r.stop, r.done)
+	return nil
+}
+
+func (r *RenderLoop) run(bufFrames int, sink func(left, right []int16) error, stop, done chan struct{}) {
+	defer close(done)
+	left := make([]int16, bufFrames)
+	right := make([]int16, bufFrames)
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		err := r.render(left, right)
+		if err == nil {
+			err = sink(left, right)
+		}
+		if err != nil {
+			r.mu.Lock()
+			r.err = err
+			r.mu.Unlock()
+			return
+		}
+	}
+}
+
+// render holds the synth's render lock so that the synth can't be closed mid-block, without
+// blocking other calls on the synth the way its state lock would
+func (r *RenderLoop) render(left, right []int16) error {
+	st := r.synth.state
+	st.renderMu.RLock()
+	defer st.renderMu.RUnlock()
+	if st.closed {
+		return fmt.Errorf("synth is closed")
+	}
+	return r.synth.WriteS16(left, right, 1, 1)
+}
+
+// Stop stops the render loop and waits for the goroutine to exit. It returns the error
+// that ended the loop early, if any. Concurrent calls all wait for the goroutine.
+func (r *RenderLoop) Stop() error {
+	// stop is cleared before closing it so that only one call closes it, done is kept until the
+	// goroutine exited so that Start can't run a second loop meanwhile
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop = nil
+	r.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	if stop != nil {
+		close(stop)
+	}
+	<-done
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done == done {
+		r.done = nil
+	}
+	return r.err
+}
+
+// ErrRenderInProgress is returned by render calls made while another one is running on the
+// same synth, if the render guard is enabled
+var ErrRenderInProgress = errors.New("render already in progress")
+
+// SetRenderGuard enables or disables the render guard. A synth is meant to be rendered by a
+// single goroutine at a time; concurrent WriteS16/WriteFloat calls don't crash, but split the
+// synth's output between the callers. With the guard enabled a render call that overlaps
+// another one fails with ErrRenderInProgress instead.
+func (s *Synth) SetRenderGuard(on bool) {
+	s.state.renderGuard.Store(on)
+}
+
+// guardRender marks a render as running if the render guard is enabled.
+// The returned func must be called once the render is done.
+func (s *Synth) guardRender() (release func(), err error) {
+	if !s.state.renderGuard.Load() {
+		// Without the guard only a render claimed with BeginRender is detected
+		if s.state.rendering.Load() {
+			return nil, ErrRenderInProgress
+		}
+		return func() {}, nil
+	}
+	if !s.state.rendering.CompareAndSwap(false, true) {
+		return nil, ErrRenderInProgress
+	}
+	return func() { s.state.rendering.Store(false) }, nil
+}
+
+// BeginRender claims the synth for a single rendering goroutine until EndRender is called,
+// so that WriteS16Unlocked and WriteFloatUnlocked can skip the render guard check on every call.
+// While claimed, guarded render calls fail with ErrRenderInProgress whether or not the guard is
+// enabled. Nothing stops other goroutines from calling the Unlocked variants; that is a data race.
+func (s *Synth) BeginRender() error {
+	if s.isClosed() {
+		return fmt.Errorf("synth is closed")
+	}
+	if !s.state.rendering.CompareAndSwap(false, true) {
+		return ErrRenderInProgress
+	}
+	return nil
+}
+
+// EndRender releases the synth claimed with BeginRender
+func (s *Synth) EndRender() {
+	s.state.rendering.Store(false)
+}
+
+var renderPool = sync.Pool{
+	New: func() any { return new([]int16) },
+}
+
+// RenderBlock renders frames frames into buffers taken from a pool and returns them together
+// with a release func that puts them back. The slices must not be used after release is called;
+// calling release again has no effect.
+// If frames is not positive, nil slices and a no-op release are returned.
+func (s *Synth) RenderBlock(frames int) (left, right []int16, release func()) {
+	if frames <= 0 {
+		return nil, nil, func() {}
+	}
+	bp := renderPool.Get().(*[]int16)
+	if cap(*bp) < 2*frames {
+		*bp = make([]int16, 2*frames)
+	}
+	buf := (*bp)[:2*frames]
+	left, right = buf[:frames], buf[frames:]
+	// RenderBlock has no error result, a block that couldn't be rendered is silent
+	if err := s.WriteS16(left, right, 1, 1); err != nil {
+		clear(buf)
+	}
+	return left, right, func() {
+		if bp != nil {
+			renderPool.Put(bp)
+			bp = nil
+		}
+	}
+}
+
+// RenderFrames renders n frames into a new buffer of interleaved left/right samples
+func (s *Synth) RenderFrames(n int) ([]int16, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid frame count: %d", n)
+	}
+	buf := make([]int16, 2*n)
+	if _, err := s.WriteS16Interleaved(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+const (
+	// offlineBlockFrames is the block size used by RenderTail and RenderRange
+	offlineBlockFrames = 512
+	// fluidBlockFrames is the block size fluidsynth renders internally; player callbacks run once per block
+	fluidBlockFrames = 64
+	// tailSilenceLevel is the peak sample value RenderTail treats as silence, about -72 dBFS
+	tailSilenceLevel = 8
+	// tailSilenceSeconds is how long the output has to stay silent for RenderTail to stop
+	tailSilenceSeconds = 0.5
+)
+
+// RenderTail renders the synth's remaining output, e.g. reverb and chorus tails after the last
+// note-off, and passes it to sink in blocks of up to 512 frames. It stops once no voices are
+// active and every sample stayed within ±8 (about -72 dBFS) for half a second, or after
+// frames frames. The slices passed to sink are reused for the next block.
+func (s *Synth) RenderTail(frames int, sink func(left, right []int16) error) error {
+	if frames <= 0 {
+		return fmt.Errorf("invalid frame count: %d", frames)
+	}
+	if sink == nil {
+		return fmt.Errorf("nil sink")
+	}
+	rate, err := s.GetSampleRate()
+	if err != nil {
+		return err
+	}
+	silenceFrames := int(rate * tailSilenceSeconds)
+
+	left := make([]int16, offlineBlockFrames)
+	right := make([]int16, offlineBlockFrames)
+	silent := 0
+	for rendered := 0; rendered < frames && silent < silenceFrames; {
+		n := min(offlineBlockFrames, frames-rendered)
+		if err := s.WriteS16(left[:n], right[:n], 1, 1); err != nil {
+			return err
+		}
+		if err := sink(left[:n], right[:n]); err != nil {
+			return err
+		}
+		rendered += n
+		if s.GetActiveVoiceCount() == 0 && isSilent(left[:n]) && isSilent(right[:n]) {
+			silent += n
+		} else {
+			silent = 0
+		}
+	}
+	return nil
+}
+
+func isSilent(buf []int16) bool {
+	for _, v := range buf {
+		if v > tailSilenceLevel || v < -tailSilenceLevel {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderRange renders the part of the player's playlist between start and end and passes it
+// to sink in blocks of up to 512 frames. The player must be created with "player.timing-source"
+// set to "sample" so that it advances with the rendered audio rather than the wall clock.
+// The seek to start is converted to ticks using the tempo at the beginning of the file (see
+// SeekSeconds), so the range is only exact for files without tempo changes before start. The range is measured in
+// rendered frames from there, and ends early when the player is done.
+func RenderRange(synth *Synth, player *Player, start, end time.Duration, sink func(left, right []int16) error) error {
+	if start < 0 || end <= start {
+		return fmt.Errorf("invalid range: %s-%s", start, end)
+	}
+	if sink == nil {
+		return fmt.Errorf("nil sink")
+	}
+	rate, err := synth.GetSampleRate()
+	if err != nil {
+		return err
+	}
+	if err := player.Play(); err != nil {
+		return err
+	}
+	defer player.Stop()
+	if start > 0 {
+		// The player only loads a file, and with it the division SeekSeconds needs, in its first
+		// callback. Render and drop single blocks until then; the seek itself is carried out by
+		// the next callback, which also silences the notes started in the dropped blocks.
+		scratch := make([]int16, fluidBlockFrames)
+		for {
+			division, err := player.GetDivision()
+			if err != nil {
+				return err
+			}
+			if division > 0 {
+				break
+			}
+			if done, err := player.IsDone(); err != nil || done {
+				return errors.Join(err, fmt.Errorf("player is done before %s", start))
+			}
+			if err := synth.WriteS16(scratch, scratch, 1, 1); err != nil {
+				return err
+			}
+		}
+		if err := player.SeekSeconds(start.Seconds()); err != nil {
+			return err
+		}
+	}
+
+	frames := int((end - start).Seconds() * rate)
+	left := make([]int16, offlineBlockFrames)
+	right := make([]int16, offlineBlockFrames)
+	for rendered := 0; rendered < frames; {
+		if done, err := player.IsDone(); err != nil || done {
+			return err
+		}
+		n := min(offlineBlockFrames, frames-rendered)
+		if err := synth.WriteS16(left[:n], right[:n], 1, 1); err != nil {
+			return err
+		}
+		if err := sink(left[:n], right[:n]); err != nil {
+			return err
+		}
+		rendered += n
+	}
+	return nil
+}
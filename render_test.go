@@ -0,0 +1,188 @@
+package fluidsynth2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSMF returns a format 0 MIDI file at 120 bpm and 480 ticks per quarter note that holds
+// middle C for the given number of seconds
+func testSMF(seconds float64) []byte {
+	ticks := int(seconds * 960)
+	var track bytes.Buffer
+	track.Write([]byte{0x00, 0x90, 60, 100})
+	writeVarLen(&track, ticks)
+	track.Write([]byte{0x80, 60, 0})
+	track.Write([]byte{0x00, 0xff, 0x2f, 0x00})
+
+	var smf bytes.Buffer
+	smf.WriteString("MThd")
+	binary.Write(&smf, binary.BigEndian, uint32(6))
+	binary.Write(&smf, binary.BigEndian, []uint16{0, 1, 480})
+	smf.WriteString("MTrk")
+	binary.Write(&smf, binary.BigEndian, uint32(track.Len()))
+	smf.Write(track.Bytes())
+	return smf.Bytes()
+}
+
+// writeVarLen writes v as a MIDI variable-length quantity
+func writeVarLen(b *bytes.Buffer, v int) {
+	for shift := 21; shift > 0; shift -= 7 {
+		if v>>shift > 0 {
+			b.WriteByte(byte(v>>shift)&0x7f | 0x80)
+		}
+	}
+	b.WriteByte(byte(v) & 0x7f)
+}
+
+func TestRenderRange(t *testing.T) {
+	tests := []struct {
+		start, end time.Duration
+	}{
+		{0, time.Second},
+		{time.Second, 2500 * time.Millisecond},
+		{3 * time.Second, 3*time.Second + time.Millisecond},
+	}
+	for _, tt := range tests {
+		settings := NewSettings()
+		settings.SetString("player.timing-source", "sample")
+		synth := NewSynth(settings)
+		player := NewPlayer(synth)
+		if err := player.AddMem(testSMF(4)); err != nil {
+			t.Fatal(err)
+		}
+		rate, err := synth.GetSampleRate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames := 0
+		err = RenderRange(&synth, &player, tt.start, tt.end, func(left, right []int16) error {
+			frames += len(left)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("[%s, %s): %v", tt.start, tt.end, err)
+		}
+		if want := int((tt.end - tt.start).Seconds() * rate); frames != want {
+			t.Errorf("[%s, %s): rendered %d frames, want %d", tt.start, tt.end, frames, want)
+		}
+		player.Close()
+		synth.Close()
+		settings.Close()
+	}
+}
+
+func TestRenderBlockReleaseTwice(t *testing.T) {
+	synth := newTestSynth(t)
+	_, _, release := synth.RenderBlock(64)
+	release()
+	release()
+	left1, _, release1 := synth.RenderBlock(64)
+	defer release1()
+	left2, _, release2 := synth.RenderBlock(64)
+	defer release2()
+	if &left1[0] == &left2[0] {
+		t.Error("two blocks share a buffer after a double release")
+	}
+}
+
+func BenchmarkRenderBlock(b *testing.B) {
+	synth := newTestSynth(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, release := synth.RenderBlock(offlineBlockFrames)
+		release()
+	}
+}
+
+func BenchmarkRenderFreshSlices(b *testing.B) {
+	synth := newTestSynth(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		left := make([]int16, offlineBlockFrames)
+		right := make([]int16, offlineBlockFrames)
+		if err := synth.WriteS16(left, right, 1, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestRenderLoop(t *testing.T) {
+	synth := newTestSynth(t)
+	loop := NewRenderLoop(&synth)
+	const bufFrames = 256
+	blocks, frames := 0, 0
+	full := make(chan struct{})
+	err := loop.Start(bufFrames, func(left, right []int16) error {
+		if blocks == 10 {
+			return nil
+		}
+		blocks++
+		frames += len(left)
+		if blocks == 10 {
+			close(full)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-full
+	if err := loop.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if frames != 10*bufFrames {
+		t.Errorf("captured %d frames, want %d", frames, 10*bufFrames)
+	}
+}
+
+func TestRenderLoopSynthClosed(t *testing.T) {
+	settings := NewSettings()
+	defer settings.Close()
+	synth := NewSynth(settings)
+	loop := NewRenderLoop(&synth)
+	rendering := make(chan struct{}, 1)
+	err := loop.Start(64, func(left, right []int16) error {
+		select {
+		case rendering <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-rendering
+	synth.Close()
+	loop.mu.Lock()
+	done := loop.done
+	loop.mu.Unlock()
+	<-done
+	if err := loop.Stop(); err == nil {
+		t.Error("render loop kept running after the synth was closed")
+	}
+}
+
+func TestRenderLoopConcurrentStop(t *testing.T) {
+	synth := newTestSynth(t)
+	loop := NewRenderLoop(&synth)
+	if err := loop.Start(64, func(left, right []int16) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loop.Stop()
+		}()
+	}
+	wg.Wait()
+	if err := loop.Start(64, func(left, right []int16) error { return nil }); err != nil {
+		t.Fatalf("restart after Stop: %v", err)
+	}
+	loop.Stop()
+}
@@ -0,0 +1,212 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// SeqClientID identifies a client of a sequencer, i.e. a synth or Go callback events are sent to
+type SeqClientID int
+
+type SeqEventType int
+
+const (
+	SEQ_NOTE    SeqEventType = C.FLUID_SEQ_NOTE
+	SEQ_NOTEON  SeqEventType = C.FLUID_SEQ_NOTEON
+	SEQ_NOTEOFF SeqEventType = C.FLUID_SEQ_NOTEOFF
+	SEQ_TIMER   SeqEventType = C.FLUID_SEQ_TIMER
+)
+
+// SeqEvent is an event to be scheduled on a sequencer. Channel, Key and Velocity are used by
+// note events, Duration (in ticks) by SEQ_NOTE, which plays a note-on and the matching note-off.
+type SeqEvent struct {
+	Type     SeqEventType
+	Dest     SeqClientID
+	Channel  uint8
+	Key      uint8
+	Velocity uint8
+	Duration uint
+}
+
+// SeqCallback is called by the sequencer when an event sent to a Go client is due
+type SeqCallback func(tick uint, t SeqEventType)
+
+// Sequencer schedules events for synths and Go clients, see NewSequencer
+type Sequencer struct {
+	ptr   *C.fluid_sequencer_t
+	state *sequencerState
+}
+
+// sequencerState holds binding-side state that is shared by all copies of a Sequencer
+type sequencerState struct {
+	mu      sync.Mutex
+	closed  bool
+	clients map[SeqClientID]unsafe.Pointer
+	synths  map[*C.fluid_synth_t]SeqClientID
+
+	metronome *metronome
+}
+
+// NewSequencer creates a sequencer. With useSystemTimer its ticks follow the system clock,
+// otherwise they advance with the audio rendered by the synths registered with RegisterSynth,
+// which is what offline rendering needs.
+func NewSequencer(useSystemTimer bool) Sequencer {
+	return Sequencer{
+		ptr: C.new_fluid_sequencer2(cbool(useSystemTimer)),
+		state: &sequencerState{
+			clients: make(map[SeqClientID]unsafe.Pointer),
+			synths:  make(map[*C.fluid_synth_t]SeqClientID),
+		},
+	}
+}
+
+// Close unregisters all clients, deletes the sequencer and frees the Go callbacks of its clients.
+//
+// A client registered with RegisterSynth or RegisterClient stays registered until it is passed
+// to UnregisterClient or the sequencer is closed; its Go callback is kept alive until then, so
+// every sequencer has to be closed to free them.
+func (s *Sequencer) Close() {
+	s.state.mu.Lock()
+	if s.state.closed {
+		s.state.mu.Unlock()
+		return
+	}
+	ids := make([]SeqClientID, 0, len(s.state.clients)+len(s.state.synths))
+	for id := range s.state.clients {
+		ids = append(ids, id)
+	}
+	for _, id := range s.state.synths {
+		ids = append(ids, id)
+	}
+	s.state.metronome = nil
+	s.state.mu.Unlock()
+
+	for _, id := range ids {
+		s.UnregisterClient(id)
+	}
+	s.state.mu.Lock()
+	s.state.closed = true
+	s.state.mu.Unlock()
+	C.delete_fluid_sequencer(s.ptr)
+}
+
+// RegisterSynth makes a synth a destination for events and returns its client ID.
+// Registering the same synth again returns the same ID.
+func (s *Sequencer) RegisterSynth(synth *Synth) (SeqClientID, error) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.closed {
+		return 0, fmt.Errorf("sequencer is closed")
+	}
+	if id, ok := s.state.synths[synth.ptr]; ok {
+		return id, nil
+	}
+	id := C.fluid_sequencer_register_fluidsynth(s.ptr, synth.ptr)
+	if id == C.FLUID_FAILED {
+		return 0, fmt.Errorf("failed to register synth")
+	}
+	s.state.synths[synth.ptr] = SeqClientID(id)
+	return SeqClientID(id), nil
+}
+
+// RegisterClient registers a Go client and returns its client ID. fn is called from the
+// sequencer's timer thread (or the rendering thread without system timer) for every event sent
+// to the client; it may schedule further events with SendAt.
+func (s *Sequencer) RegisterClient(name string, fn SeqCallback) (SeqClientID, error) {
+	if fn == nil {
+		return 0, fmt.Errorf("nil sequencer callback")
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.closed {
+		return 0, fmt.Errorf("sequencer is closed")
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	handle := newCallbackHandle(fn)
+	id := registerGoSeqClient(s.ptr, cname, handle)
+	if id == C.FLUID_FAILED {
+		deleteCallbackHandle(handle)
+		return 0, fmt.Errorf("failed to register sequencer client: %s", name)
+	}
+	s.state.clients[SeqClientID(id)] = handle
+	return SeqClientID(id), nil
+}
+
+// UnregisterClient removes a synth or Go client and the events still queued for it. The Go
+// callback of a client is freed once fluidsynth has unregistered it and is not called again.
+// It must not be called from the callback of a client of the same sequencer: fluidsynth waits
+// for running callbacks to return.
+func (s *Sequencer) UnregisterClient(id SeqClientID) {
+	s.state.mu.Lock()
+	closed := s.state.closed
+	s.state.mu.Unlock()
+	if closed {
+		return
+	}
+	C.fluid_sequencer_unregister_client(s.ptr, C.fluid_seq_id_t(id))
+	s.state.mu.Lock()
+	handle, ok := s.state.clients[id]
+	delete(s.state.clients, id)
+	for synth, synthID := range s.state.synths {
+		if synthID == id {
+			delete(s.state.synths, synth)
+		}
+	}
+	s.state.mu.Unlock()
+	if ok {
+		deleteCallbackHandle(handle)
+	}
+}
+
+//export goSeqClientCallback
+func goSeqClientCallback(time C.uint, event *C.fluid_event_t, seq *C.fluid_sequencer_t, data unsafe.Pointer) {
+	t := SeqEventType(C.fluid_event_get_type(event))
+	if t == C.FLUID_SEQ_UNREGISTERING {
+		return
+	}
+	fn := callbackValue(data).(SeqCallback)
+	fn(uint(time), t)
+}
+
+// SendAt schedules an event at tick, which is relative to the current tick unless absolute is set
+func (s *Sequencer) SendAt(ev SeqEvent, tick uint, absolute bool) error {
+	evt := C.new_fluid_event()
+	if evt == nil {
+		return fmt.Errorf("failed to create sequencer event")
+	}
+	defer C.delete_fluid_event(evt)
+	C.fluid_event_set_source(evt, -1)
+	C.fluid_event_set_dest(evt, C.fluid_seq_id_t(ev.Dest))
+	switch ev.Type {
+	case SEQ_NOTE:
+		C.fluid_event_note(evt, C.int(ev.Channel), C.short(ev.Key), C.short(ev.Velocity), C.uint(ev.Duration))
+	case SEQ_NOTEON:
+		C.fluid_event_noteon(evt, C.int(ev.Channel), C.short(ev.Key), C.short(ev.Velocity))
+	case SEQ_NOTEOFF:
+		C.fluid_event_noteoff(evt, C.int(ev.Channel), C.short(ev.Key))
+	case SEQ_TIMER:
+		C.fluid_event_timer(evt, nil)
+	default:
+		return fmt.Errorf("invalid sequencer event type: %d", ev.Type)
+	}
+	if C.fluid_sequencer_send_at(s.ptr, evt, C.uint(tick), cbool(absolute)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to schedule event: type=%d, tick=%d", ev.Type, tick)
+	}
+	return nil
+}
+
+// GetTick returns the current tick of the sequencer
+func (s *Sequencer) GetTick() uint {
+	return uint(C.fluid_sequencer_get_tick(s.ptr))
+}
+
+// GetTimeScale returns the number of ticks per second, 1000 by default
+func (s *Sequencer) GetTimeScale() float64 {
+	return float64(C.fluid_sequencer_get_time_scale(s.ptr))
+}
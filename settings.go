@@ -8,13 +8,39 @@ package fluidsynth2
 */
 import "C"
 import (
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"unsafe"
 )
 
 var settingNames map[string]*C.char
 var nSettings = 0
 
+// openSettings tracks the settings that have not been closed yet, so that
+// copies held by other objects can tell whether they are still usable.
+// The value counts the objects that keep using the settings after creation:
+//
+//	Settings <- Synth        (released by Synth.Close)
+//	Settings <- AudioDriver  (released by AudioDriver.Close)
+//
+// Players and FileRenderers only reference a Synth, which in turn keeps its
+// Settings alive. Settings.Close fails while the count is not zero.
+var openSettings = make(map[*C.fluid_settings_t]int)
+var openSettingsMu sync.Mutex
+
+type SettingType int
+
+const (
+	SETTING_TYPE_NONE SettingType = C.FLUID_NO_TYPE
+	SETTING_TYPE_NUM  SettingType = C.FLUID_NUM_TYPE
+	SETTING_TYPE_INT  SettingType = C.FLUID_INT_TYPE
+	SETTING_TYPE_STR  SettingType = C.FLUID_STR_TYPE
+	SETTING_TYPE_SET  SettingType = C.FLUID_SET_TYPE
+)
+
 type Settings struct {
 	ptr *C.fluid_settings_t
 }
@@ -24,39 +50,112 @@ func NewSettings() Settings {
 		settingNames = make(map[string]*C.char)
 	}
 	nSettings++
-	return Settings{ptr: C.new_fluid_settings()}
+	ptr := C.new_fluid_settings()
+	openSettingsMu.Lock()
+	openSettings[ptr] = 0
+	openSettingsMu.Unlock()
+	return Settings{ptr: ptr}
 }
 
-func (s *Settings) Close() {
+// Close deletes the settings. It fails if synths or audio drivers created with them are still open.
+func (s *Settings) Close() error {
+	openSettingsMu.Lock()
+	defer openSettingsMu.Unlock()
+	refs, ok := openSettings[s.ptr]
+	if !ok {
+		return nil
+	}
+	if refs > 0 {
+		return fmt.Errorf("settings are still used by %d synths or audio drivers", refs)
+	}
+	delete(openSettings, s.ptr)
 	C.delete_fluid_settings(s.ptr)
+	return nil
+}
+
+func (s *Settings) isOpen() bool {
+	openSettingsMu.Lock()
+	defer openSettingsMu.Unlock()
+	_, ok := openSettings[s.ptr]
+	return ok
+}
+
+// retain and release count the objects depending on the settings, see openSettings
+func (s *Settings) retain() {
+	openSettingsMu.Lock()
+	if _, ok := openSettings[s.ptr]; ok {
+		openSettings[s.ptr]++
+	}
+	openSettingsMu.Unlock()
+}
+
+func (s *Settings) release() {
+	openSettingsMu.Lock()
+	if refs, ok := openSettings[s.ptr]; ok && refs > 0 {
+		openSettings[s.ptr]--
+	}
+	openSettingsMu.Unlock()
 }
 
 func (s *Settings) SetInt(name string, val int) bool {
-	return C.fluid_settings_setint(s.ptr, cname(name), C.int(val)) == 1
+	return C.fluid_settings_setint(s.ptr, cname(name), C.int(val)) == C.FLUID_OK
 }
 
 func (s *Settings) SetNum(name string, val float64) bool {
-	return C.fluid_settings_setnum(s.ptr, cname(name), C.double(val)) == 1
+	return C.fluid_settings_setnum(s.ptr, cname(name), C.double(val)) == C.FLUID_OK
 }
 
 func (s *Settings) SetString(name, val string) bool {
 	cval := C.CString(val)
 	defer C.free(unsafe.Pointer(cval))
-	return C.fluid_settings_setstr(s.ptr, cname(name), cval) == 1
+	return C.fluid_settings_setstr(s.ptr, cname(name), cval) == C.FLUID_OK
 
 }
 
 func (s *Settings) GetInt(name string, val *int) bool {
-	return C.fluid_settings_getint(s.ptr, cname(name), (*C.int)(unsafe.Pointer(val))) == 1
+	var cval C.int
+	ok := (C.fluid_settings_getint(s.ptr, cname(name), &cval) == C.FLUID_OK)
+	if ok {
+		*val = int(cval)
+	}
+	return ok
 }
 
 func (s *Settings) GetNum(name string, val *float64) bool {
-	return C.fluid_settings_getnum(s.ptr, cname(name), (*C.double)(unsafe.Pointer(val))) == 1
+	return C.fluid_settings_getnum(s.ptr, cname(name), (*C.double)(unsafe.Pointer(val))) == C.FLUID_OK
+}
+
+func (s *Settings) GetString(name string, val *string) bool {
+	var cstr *C.char
+	ok := (C.fluid_settings_dupstr(s.ptr, cname(name), &cstr) == C.FLUID_OK)
+	if ok {
+		*val = C.GoString(cstr)
+		C.fluid_free(unsafe.Pointer(cstr))
+	}
+	return ok
+}
+
+func (s *Settings) GetIntDefault(name string, val *int) bool {
+	var cval C.int
+	ok := (C.fluid_settings_getint_default(s.ptr, cname(name), &cval) == C.FLUID_OK)
+	if ok {
+		*val = int(cval)
+	}
+	return ok
+}
+
+func (s *Settings) GetNumDefault(name string, val *float64) bool {
+	var cval C.double
+	ok := (C.fluid_settings_getnum_default(s.ptr, cname(name), &cval) == C.FLUID_OK)
+	if ok {
+		*val = float64(cval)
+	}
+	return ok
 }
 
 func (s *Settings) GetStringDefault(name string, val *string) bool {
 	var cstr *C.char
-	ok := (C.fluid_settings_getstr_default(s.ptr, cname(name), &cstr) == 1)
+	ok := (C.fluid_settings_getstr_default(s.ptr, cname(name), &cstr) == C.FLUID_OK)
 	if ok {
 		*val = C.GoString(cstr)
 	}
@@ -71,3 +170,402 @@ func (s *Settings) GetOptions(name string) []string {
 	C.free(unsafe.Pointer(options))
 	return strings.Split(optionsString, ", ")
 }
+
+// GetType returns the type of a setting, SETTING_TYPE_NONE if it doesn't exist
+func (s *Settings) GetType(name string) SettingType {
+	return SettingType(C.fluid_settings_get_type(s.ptr, cname(name)))
+}
+
+type settingEntry struct {
+	name string
+	t    SettingType
+}
+
+// Foreach calls fn for every setting, in alphabetical order
+func (s *Settings) Foreach(fn func(name string, t SettingType)) {
+	var entries []settingEntry
+	data := newCallbackHandle(&entries)
+	settingsForeach(s.ptr, data)
+	deleteCallbackHandle(data)
+	for _, e := range entries {
+		fn(e.name, e.t)
+	}
+}
+
+//export goSettingsForeach
+func goSettingsForeach(data unsafe.Pointer, name *C.char, t C.int) {
+	entries := callbackValue(data).(*[]settingEntry)
+	*entries = append(*entries, settingEntry{C.GoString(name), SettingType(t)})
+}
+
+// Clone returns a new Settings with the same values. Changes to the clone don't affect the original.
+func (s *Settings) Clone() (*Settings, error) {
+	values, err := s.Export()
+	if err != nil {
+		return nil, err
+	}
+	clone := NewSettings()
+	if err := clone.Import(values); err != nil {
+		clone.Close()
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// Get returns the value of a setting together with its type. The value is an int,
+// float64 or string depending on the type.
+func (s *Settings) Get(name string) (any, SettingType, error) {
+	t := s.GetType(name)
+	switch t {
+	case SETTING_TYPE_INT:
+		var val int
+		if s.GetInt(name, &val) {
+			return val, t, nil
+		}
+	case SETTING_TYPE_NUM:
+		var val float64
+		if s.GetNum(name, &val) {
+			return val, t, nil
+		}
+	case SETTING_TYPE_STR:
+		var val string
+		if s.GetString(name, &val) {
+			return val, t, nil
+		}
+	case SETTING_TYPE_NONE:
+		return nil, t, fmt.Errorf("unknown setting: %s", name)
+	default:
+		return nil, t, fmt.Errorf("setting has no value: %s", name)
+	}
+	return nil, t, fmt.Errorf("failed to get setting: %s", name)
+}
+
+// SettingHints describe how a setting is meant to be edited
+type SettingHints struct {
+	BoundedBelow bool // numeric settings with a minimum, see GetIntRange and GetNumRange
+	BoundedAbove bool // numeric settings with a maximum
+	Toggled      bool // integer settings that are on (1) or off (0)
+	OptionList   bool // string settings limited to GetOptions
+}
+
+// GetHints returns the hints of a setting. fluidsynth 2 dropped the logarithmic and filename
+// hints of earlier versions. fluidsynth uses the same bit for BoundedAbove and OptionList,
+// so it is decoded according to the type of the setting.
+func (s *Settings) GetHints(name string) (SettingHints, error) {
+	var hints C.int
+	if C.fluid_settings_get_hints(s.ptr, cname(name), &hints) != C.FLUID_OK {
+		return SettingHints{}, fmt.Errorf("failed to get hints of %s", name)
+	}
+	h := SettingHints{
+		BoundedBelow: hints&C.FLUID_HINT_BOUNDED_BELOW != 0,
+		Toggled:      hints&C.FLUID_HINT_TOGGLED != 0,
+	}
+	if s.GetType(name) == SETTING_TYPE_STR {
+		h.OptionList = hints&C.FLUID_HINT_OPTIONLIST != 0
+	} else {
+		h.BoundedAbove = hints&C.FLUID_HINT_BOUNDED_ABOVE != 0
+	}
+	return h, nil
+}
+
+// GetIntRange returns the valid range of an integer setting
+func (s *Settings) GetIntRange(name string) (min, max int, err error) {
+	var cmin, cmax C.int
+	if C.fluid_settings_getint_range(s.ptr, cname(name), &cmin, &cmax) != C.FLUID_OK {
+		return 0, 0, fmt.Errorf("failed to get range of setting: %s", name)
+	}
+	return int(cmin), int(cmax), nil
+}
+
+// GetNumRange returns the valid range of a numeric setting
+func (s *Settings) GetNumRange(name string) (min, max float64, err error) {
+	var cmin, cmax C.double
+	if C.fluid_settings_getnum_range(s.ptr, cname(name), &cmin, &cmax) != C.FLUID_OK {
+		return 0, 0, fmt.Errorf("failed to get range of setting: %s", name)
+	}
+	return float64(cmin), float64(cmax), nil
+}
+
+// ValidateInt checks that val is within the range of an integer setting
+func (s *Settings) ValidateInt(name string, val int) error {
+	min, max, err := s.GetIntRange(name)
+	if err != nil {
+		return err
+	}
+	if val < min {
+		return fmt.Errorf("value of %s is below the minimum of %d: %d", name, min, val)
+	}
+	if val > max {
+		return fmt.Errorf("value of %s is above the maximum of %d: %d", name, max, val)
+	}
+	return nil
+}
+
+// ValidateNum checks that val is within the range of a numeric setting
+func (s *Settings) ValidateNum(name string, val float64) error {
+	min, max, err := s.GetNumRange(name)
+	if err != nil {
+		return err
+	}
+	if val < min {
+		return fmt.Errorf("value of %s is below the minimum of %f: %f", name, min, val)
+	}
+	if val > max {
+		return fmt.Errorf("value of %s is above the maximum of %f: %f", name, max, val)
+	}
+	return nil
+}
+
+// SetIntValidated is like SetInt, but reports why the value was rejected
+func (s *Settings) SetIntValidated(name string, val int) error {
+	if err := s.ValidateInt(name, val); err != nil {
+		return err
+	}
+	if !s.SetInt(name, val) {
+		return fmt.Errorf("failed to set %s: %d", name, val)
+	}
+	return nil
+}
+
+// SetNumValidated is like SetNum, but reports why the value was rejected
+func (s *Settings) SetNumValidated(name string, val float64) error {
+	if err := s.ValidateNum(name, val); err != nil {
+		return err
+	}
+	if !s.SetNum(name, val) {
+		return fmt.Errorf("failed to set %s: %f", name, val)
+	}
+	return nil
+}
+
+// SetStringValidated is like SetString, but checks val against the setting's options first.
+// Settings without an option list accept any string.
+func (s *Settings) SetStringValidated(name, val string) error {
+	if t := s.GetType(name); t != SETTING_TYPE_STR {
+		return fmt.Errorf("%s is not a string setting", name)
+	}
+	options := s.GetOptions(name)
+	if len(options) == 1 && options[0] == "" {
+		options = nil
+	}
+	if len(options) > 0 && !slices.Contains(options, val) {
+		return fmt.Errorf("invalid value for %s: %s (valid options: %s)", name, val, strings.Join(options, ", "))
+	}
+	if !s.SetString(name, val) {
+		return fmt.Errorf("failed to set %s: %s", name, val)
+	}
+	return nil
+}
+
+// Export returns the values of all settings, keyed by name
+func (s *Settings) Export() (map[string]any, error) {
+	values := make(map[string]any)
+	var errs []error
+	s.Foreach(func(name string, t SettingType) {
+		if t != SETTING_TYPE_INT && t != SETTING_TYPE_NUM && t != SETTING_TYPE_STR {
+			return
+		}
+		val, _, err := s.Get(name)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		values[name] = val
+	})
+	return values, errors.Join(errs...)
+}
+
+// Import sets the settings in m, using the setter matching each setting's type. Numbers may
+// be given as any int or float type, so that values decoded from JSON can be imported.
+// Settings that already have the given value are not set again, which skips settings that
+// can't be changed once a synth is running as long as their value is the same.
+func (s *Settings) Import(m map[string]any) error {
+	var errs []error
+	for name, v := range m {
+		cur, t, err := s.Get(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		switch t {
+		case SETTING_TYPE_INT:
+			val, ok := toInt(v)
+			if !ok {
+				errs = append(errs, fmt.Errorf("invalid value for integer setting %s: %v", name, v))
+			} else if val != cur && !s.SetInt(name, val) {
+				errs = append(errs, fmt.Errorf("failed to set %s: %d", name, val))
+			}
+		case SETTING_TYPE_NUM:
+			val, ok := toFloat(v)
+			if !ok {
+				errs = append(errs, fmt.Errorf("invalid value for numeric setting %s: %v", name, v))
+			} else if val != cur && !s.SetNum(name, val) {
+				errs = append(errs, fmt.Errorf("failed to set %s: %f", name, val))
+			}
+		case SETTING_TYPE_STR:
+			val, ok := v.(string)
+			if !ok {
+				errs = append(errs, fmt.Errorf("invalid value for string setting %s: %v", name, v))
+			} else if val != cur && !s.SetString(name, val) {
+				errs = append(errs, fmt.Errorf("failed to set %s: %s", name, val))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float32:
+		return int(n), float32(int(n)) == n
+	case float64:
+		return int(n), float64(int(n)) == n
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// SetPolyphony sets "synth.polyphony", the number of voices a synth can play at once.
+// Once the limit is reached, new notes steal the voices with the lowest overflow score, so
+// GetActiveVoiceCount never exceeds it; see SetOverflowPriorities.
+func (s *Settings) SetPolyphony(voices int) error {
+	return s.SetIntValidated("synth.polyphony", voices)
+}
+
+// SetDynamicSampleLoading sets "synth.dynamic-sample-loading". When on, samples are only loaded
+// once a preset using them is selected, which saves memory but delays the first notes of new
+// presets. It applies to soundfonts loaded afterwards, and doesn't change voice stealing.
+func (s *Settings) SetDynamicSampleLoading(on bool) error {
+	return s.SetIntValidated("synth.dynamic-sample-loading", int(cbool(on)))
+}
+
+// deterministicSettings are the settings changed by SetDeterministic, with their deterministic values
+var deterministicSettings = map[string]any{
+	"synth.cpu-cores":              1,
+	"synth.dynamic-sample-loading": 0,
+	"player.timing-source":         "sample",
+}
+
+// SetDeterministic changes the settings for reproducible offline rendering: a single render
+// thread, all samples loaded up front and players timed by rendered samples instead of the system
+// clock. Switching it off restores the defaults. fluidsynth reads these settings when synths and
+// players are created, so they only apply to the ones created afterwards from these Settings.
+//
+// Chorus and reverb have no random state, but WriteS16 dithers with a noise table that every
+// write advances, so 16-bit output is only identical if the same sequence of writes is made on
+// a fresh synth. WriteFloat output is not dithered.
+func (s *Settings) SetDeterministic(on bool) error {
+	for name, v := range deterministicSettings {
+		switch v := v.(type) {
+		case int:
+			if !on && !s.GetIntDefault(name, &v) {
+				return fmt.Errorf("failed to read default of %s", name)
+			}
+			if !s.SetInt(name, v) {
+				return fmt.Errorf("failed to set %s: %d", name, v)
+			}
+		case string:
+			if !on && !s.GetStringDefault(name, &v) {
+				return fmt.Errorf("failed to read default of %s", name)
+			}
+			if !s.SetString(name, v) {
+				return fmt.Errorf("failed to set %s: %s", name, v)
+			}
+		}
+	}
+	return nil
+}
+
+// IsDeterministic returns true if all settings changed by SetDeterministic have their deterministic values
+func (s *Settings) IsDeterministic() bool {
+	for name, want := range deterministicSettings {
+		switch want := want.(type) {
+		case int:
+			var v int
+			if !s.GetInt(name, &v) || v != want {
+				return false
+			}
+		case string:
+			var v string
+			if !s.GetString(name, &v) || v != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// OverflowPriorities weight the properties fluidsynth scores voices by when it has to steal one:
+// the voice with the lowest score is stolen. Positive values protect voices with the property,
+// e.g. a high Percussion keeps drums playing, while Released and Sustained are usually negative
+// so that voices in their release phase or held by the sustain pedal go first. Age and Volume
+// are weighted per second of playing time and by the voice's current volume.
+type OverflowPriorities struct {
+	Percussion float64
+	Sustained  float64
+	Released   float64
+	Age        float64
+	Volume     float64
+	Important  float64
+}
+
+var overflowSettings = []string{
+	"synth.overflow.percussion",
+	"synth.overflow.sustained",
+	"synth.overflow.released",
+	"synth.overflow.age",
+	"synth.overflow.volume",
+	"synth.overflow.important",
+}
+
+func (p *OverflowPriorities) fields() []*float64 {
+	return []*float64{&p.Percussion, &p.Sustained, &p.Released, &p.Age, &p.Volume, &p.Important}
+}
+
+// SetOverflowPriorities sets the "synth.overflow.*" settings used for voice stealing.
+// The important weight applies to the channels listed in "synth.overflow.important-channels".
+func (s *Settings) SetOverflowPriorities(p OverflowPriorities) error {
+	for i, v := range p.fields() {
+		if err := s.SetNumValidated(overflowSettings[i], *v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetOverflowPriorities returns the "synth.overflow.*" settings used for voice stealing
+func (s *Settings) GetOverflowPriorities() (OverflowPriorities, error) {
+	var p OverflowPriorities
+	for i, v := range p.fields() {
+		if !s.GetNum(overflowSettings[i], v) {
+			return OverflowPriorities{}, fmt.Errorf("failed to read %s", overflowSettings[i])
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,60 @@
+package fluidsynth2
+
+import "fmt"
+
+// SettingsBuilder collects setting values and applies them to new Settings in one go:
+//
+//	settings, err := NewSettingsBuilder().
+//		Num("synth.gain", 0.5).
+//		Int("synth.polyphony", 128).
+//		Build()
+type SettingsBuilder struct {
+	ops []func(s *Settings) error
+}
+
+func NewSettingsBuilder() *SettingsBuilder {
+	return &SettingsBuilder{}
+}
+
+func (b *SettingsBuilder) Int(name string, v int) *SettingsBuilder {
+	b.ops = append(b.ops, func(s *Settings) error {
+		if !s.SetInt(name, v) {
+			return fmt.Errorf("failed to set %s: %d", name, v)
+		}
+		return nil
+	})
+	return b
+}
+
+func (b *SettingsBuilder) Num(name string, v float64) *SettingsBuilder {
+	b.ops = append(b.ops, func(s *Settings) error {
+		if !s.SetNum(name, v) {
+			return fmt.Errorf("failed to set %s: %f", name, v)
+		}
+		return nil
+	})
+	return b
+}
+
+func (b *SettingsBuilder) Str(name, v string) *SettingsBuilder {
+	b.ops = append(b.ops, func(s *Settings) error {
+		if !s.SetString(name, v) {
+			return fmt.Errorf("failed to set %s: %s", name, v)
+		}
+		return nil
+	})
+	return b
+}
+
+// Build creates the settings and applies the values in the order they were added.
+// It stops at the first value that can't be set, in which case the settings are closed again.
+func (b *SettingsBuilder) Build() (*Settings, error) {
+	s := NewSettings()
+	for _, op := range b.ops {
+		if err := op(&s); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return &s, nil
+}
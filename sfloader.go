@@ -0,0 +1,246 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// SFLoader opens soundfont data by name. It lets SFLoad read soundfonts from
+// memory, archives or the network instead of the filesystem.
+type SFLoader interface {
+	// Open returns the soundfont data for name. If the loader doesn't know the
+	// name it should return an error, the next loader is then tried.
+	Open(name string) (io.ReadSeeker, error)
+}
+
+// sfLoaderStates are the states of the open synths with Go loaders. fluidsynth reopens
+// soundfonts to load samples on demand without saying for which synth, so goSFLoaderOpen
+// then looks through all of them.
+var (
+	sfLoaderStates   = make(map[*synthState]bool)
+	sfLoaderStatesMu sync.Mutex
+)
+
+// RegisterSFLoader makes SFLoad look up soundfonts through loader before falling back
+// to the filesystem. Loaders are tried from the most recently registered one. They only
+// apply to this synth and are released when it is closed.
+//
+// fluidsynth calls the loader from the goroutine calling SFLoad, and, when
+// "synth.dynamic-sample-loading" is enabled, again from whichever thread selects a preset.
+// Those later calls reuse the loader that first opened the soundfont's name; if several synths
+// loaded the same name through different loaders, any of them may be used. Loaders and the
+// readers they return must therefore be safe to use from any thread.
+// The buffers fluidsynth reads into are owned by fluidsynth and only valid during a Read call.
+// If a returned reader implements io.Closer it is closed once fluidsynth is done with it.
+func (s *Synth) RegisterSFLoader(loader SFLoader) error {
+	if loader == nil {
+		return fmt.Errorf("nil soundfont loader")
+	}
+	if err := s.installGoSFLoader(); err != nil {
+		return err
+	}
+	s.state.mu.Lock()
+	s.state.sfLoaders = append(s.state.sfLoaders, loader)
+	s.state.mu.Unlock()
+	return nil
+}
+
+// unregisterSFLoader removes a loader added with RegisterSFLoader. Soundfonts it already
+// opened can still be reopened through it until the synth is closed.
+func (s *Synth) unregisterSFLoader(loader SFLoader) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if i := slices.Index(s.state.sfLoaders, loader); i >= 0 {
+		s.state.sfLoaders = slices.Delete(s.state.sfLoaders, i, i+1)
+	}
+}
+
+// installGoSFLoader adds a fluidsynth loader reading through the registered Go loaders.
+// It is only added once per synth, as fluidsynth has no way to remove it again.
+func (s *Synth) installGoSFLoader() error {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.closed {
+		return fmt.Errorf("synth is closed")
+	}
+	if s.state.sfHandle != nil {
+		return nil
+	}
+	loader := C.new_fluid_defsfloader(s.settings.ptr)
+	if loader == nil {
+		return fmt.Errorf("failed to create soundfont loader")
+	}
+	if err := setGoSFLoaderCallbacks(loader); err != nil {
+		C.delete_fluid_sfloader(loader)
+		return fmt.Errorf("failed to set soundfont loader callbacks")
+	}
+	C.fluid_synth_add_sfloader(s.ptr, loader)
+	s.state.sfHandle = newCallbackHandle(s.state)
+	sfLoaderStatesMu.Lock()
+	sfLoaderStates[s.state] = true
+	sfLoaderStatesMu.Unlock()
+	return nil
+}
+
+// sfLoaderHandle returns the state handle for the loader callbacks, nil without Go loaders
+func (s *Synth) sfLoaderHandle() unsafe.Pointer {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.sfHandle
+}
+
+// dropSFLoaders releases the Go loaders of a closed synth, called with st.mu held
+func (st *synthState) dropSFLoaders() {
+	if st.sfHandle == nil {
+		return
+	}
+	sfLoaderStatesMu.Lock()
+	delete(sfLoaderStates, st)
+	sfLoaderStatesMu.Unlock()
+	deleteCallbackHandle(st.sfHandle)
+	st.sfHandle = nil
+	st.sfLoaders = nil
+	st.sfOpened = nil
+}
+
+// openSF opens name through the synth's loaders, newest first, then through the loader that
+// opened name before
+func (st *synthState) openSF(name string) io.ReadSeeker {
+	st.mu.Lock()
+	loaders := slices.Clone(st.sfLoaders)
+	if l, ok := st.sfOpened[name]; ok {
+		loaders = slices.Insert(loaders, 0, l)
+	}
+	st.mu.Unlock()
+	for i := len(loaders) - 1; i >= 0; i-- {
+		r, err := loaders[i].Open(name)
+		if err == nil && r != nil {
+			st.mu.Lock()
+			if !st.closed {
+				if st.sfOpened == nil {
+					st.sfOpened = make(map[string]SFLoader)
+				}
+				st.sfOpened[name] = loaders[i]
+			}
+			st.mu.Unlock()
+			return r
+		}
+	}
+	return nil
+}
+
+// reopenSF opens name through the loader that opened it on any synth
+func reopenSF(name string) io.ReadSeeker {
+	sfLoaderStatesMu.Lock()
+	states := make([]*synthState, 0, len(sfLoaderStates))
+	for st := range sfLoaderStates {
+		states = append(states, st)
+	}
+	sfLoaderStatesMu.Unlock()
+	for _, st := range states {
+		st.mu.Lock()
+		l, ok := st.sfOpened[name]
+		st.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if r, err := l.Open(name); err == nil && r != nil {
+			return r
+		}
+	}
+	return nil
+}
+
+//export goSFLoaderOpen
+func goSFLoaderOpen(filename *C.char) unsafe.Pointer {
+	name := C.GoString(filename)
+	var r io.ReadSeeker
+	if handle := sfloadState(); handle != nil {
+		r = callbackValue(handle).(*synthState).openSF(name)
+	} else {
+		r = reopenSF(name)
+	}
+	if r == nil {
+		return nil
+	}
+	return newCallbackHandle(r)
+}
+
+//export goSFLoaderRead
+func goSFLoaderRead(buf unsafe.Pointer, count C.fluid_long_long_t, handle unsafe.Pointer) C.int {
+	r := callbackValue(handle).(io.ReadSeeker)
+	if _, err := io.ReadFull(r, unsafe.Slice((*byte)(buf), int(count))); err != nil {
+		return C.FLUID_FAILED
+	}
+	return C.FLUID_OK
+}
+
+//export goSFLoaderSeek
+func goSFLoaderSeek(handle unsafe.Pointer, offset C.fluid_long_long_t, origin C.int) C.int {
+	r := callbackValue(handle).(io.ReadSeeker)
+	// SEEK_SET, SEEK_CUR and SEEK_END have the same values as io.SeekStart, io.SeekCurrent and io.SeekEnd
+	if _, err := r.Seek(int64(offset), int(origin)); err != nil {
+		return C.FLUID_FAILED
+	}
+	return C.FLUID_OK
+}
+
+//export goSFLoaderTell
+func goSFLoaderTell(handle unsafe.Pointer) C.fluid_long_long_t {
+	r := callbackValue(handle).(io.ReadSeeker)
+	pos, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return C.FLUID_FAILED
+	}
+	return C.fluid_long_long_t(pos)
+}
+
+//export goSFLoaderClose
+func goSFLoaderClose(handle unsafe.Pointer) C.int {
+	r := callbackValue(handle).(io.ReadSeeker)
+	deleteCallbackHandle(handle)
+	if c, ok := r.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return C.FLUID_FAILED
+		}
+	}
+	return C.FLUID_OK
+}
+
+// readerLoader is the temporary loader used by SFLoadReader
+type readerLoader struct {
+	name string
+	r    io.ReaderAt
+	size int64
+}
+
+func (l *readerLoader) Open(name string) (io.ReadSeeker, error) {
+	if name != l.name {
+		return nil, fmt.Errorf("unknown soundfont: %s", name)
+	}
+	return io.NewSectionReader(l.r, 0, l.size), nil
+}
+
+// SFLoadReader loads a soundfont from the first size bytes of r, e.g. an embedded asset, and
+// returns its ID. name is what the soundfont is called in the synth. The temporary loader is
+// only registered on this synth for the duration of the call. With dynamic sample loading
+// off, r is only read during the call. With "synth.dynamic-sample-loading" on, fluidsynth reads
+// samples whenever a preset is selected, so r must stay valid until the synth is closed.
+func (s *Synth) SFLoadReader(name string, r io.ReaderAt, size int64, resetPresets bool) (int, error) {
+	if r == nil || size <= 0 {
+		return 0, fmt.Errorf("invalid soundfont data: %s", name)
+	}
+	loader := &readerLoader{name, r, size}
+	if err := s.RegisterSFLoader(loader); err != nil {
+		return 0, err
+	}
+	defer s.unregisterSFLoader(loader)
+	return s.SFLoad(name, resetPresets)
+}
@@ -0,0 +1,80 @@
+package fluidsynth2
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+// memLoader serves soundfonts from memory
+type memLoader map[string][]byte
+
+func (l memLoader) Open(name string) (io.ReadSeeker, error) {
+	data, ok := l[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown soundfont: %s", name)
+	}
+	return bytes.NewReader(data), nil
+}
+
+func TestRegisterSFLoader(t *testing.T) {
+	data, err := os.ReadFile(testSoundFont(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	synth, other := newTestSynth(t), newTestSynth(t)
+	if err := synth.RegisterSFLoader(memLoader{"mem.sf2": data}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := synth.SFLoad("mem.sf2", true); err != nil {
+		t.Errorf("loading through the registered loader: %v", err)
+	}
+	if _, err := other.SFLoad("mem.sf2", true); err == nil {
+		t.Error("a loader registered on one synth was used by another")
+	}
+}
+
+func TestSFLoaderReleasedOnClose(t *testing.T) {
+	settings := NewSettings()
+	defer settings.Close()
+	synth := NewSynth(settings)
+	if err := synth.RegisterSFLoader(memLoader{}); err != nil {
+		t.Fatal(err)
+	}
+	st := synth.state
+	synth.Close()
+	sfLoaderStatesMu.Lock()
+	registered := sfLoaderStates[st]
+	sfLoaderStatesMu.Unlock()
+	if registered || st.sfLoaders != nil || st.sfHandle != nil {
+		t.Error("loaders of a closed synth are still registered")
+	}
+	if err := synth.RegisterSFLoader(memLoader{}); err == nil {
+		t.Error("registered a loader on a closed synth")
+	}
+}
+
+func TestSFLoadReaderUnregisters(t *testing.T) {
+	synth := newTestSynth(t)
+	data := []byte("not a soundfont")
+	synth.SFLoadReader("embedded.sf2", bytes.NewReader(data), int64(len(data)), true)
+	if n := len(synth.state.sfLoaders); n != 0 {
+		t.Errorf("%d loaders still registered after SFLoadReader", n)
+	}
+}
+
+func TestSFLoadReader(t *testing.T) {
+	data, err := os.ReadFile(testSoundFont(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	synth := newTestSynth(t)
+	if _, err := synth.SFLoadReader("embedded.sf2", bytes.NewReader(data), int64(len(data)), true); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(synth.state.sfLoaders); n != 0 {
+		t.Errorf("%d loaders still registered after SFLoadReader", n)
+	}
+}
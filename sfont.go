@@ -0,0 +1,171 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "fmt"
+
+// SoundFont is a soundfont loaded into a synth
+type SoundFont struct {
+	ptr *C.fluid_sfont_t
+}
+
+// ID returns the ID the soundfont was assigned when it was added to a synth
+func (f *SoundFont) ID() int {
+	return int(C.fluid_sfont_get_id(f.ptr))
+}
+
+// Name returns the name of the soundfont, usually the path it was loaded from
+func (f *SoundFont) Name() string {
+	return C.GoString(C.fluid_sfont_get_name(f.ptr))
+}
+
+// CountPresets returns the number of presets in the soundfont. It walks fluidsynth's preset
+// list, which is cheap as no samples are loaded, but takes time linear in the number of presets.
+// The soundfont has a single iteration position, so it must not run concurrently with other
+// preset iterations of the same soundfont.
+func (f *SoundFont) CountPresets() int {
+	n := 0
+	C.fluid_sfont_iteration_start(f.ptr)
+	for C.fluid_sfont_iteration_next(f.ptr) != nil {
+		n++
+	}
+	return n
+}
+
+// SFCount returns the number of loaded soundfonts
+func (s *Synth) SFCount() int {
+	return int(C.fluid_synth_sfcount(s.ptr))
+}
+
+// GetSFont returns a loaded soundfont by its index in the font stack, 0 being the last loaded font
+func (s *Synth) GetSFont(num int) (*SoundFont, error) {
+	if num < 0 {
+		return nil, fmt.Errorf("invalid soundfont index: %d", num)
+	}
+	sfont := C.fluid_synth_get_sfont(s.ptr, C.uint(num))
+	if sfont == nil {
+		return nil, fmt.Errorf("no soundfont at index: %d", num)
+	}
+	return &SoundFont{sfont}, nil
+}
+
+// GetSFontByID returns a loaded soundfont by its ID
+func (s *Synth) GetSFontByID(id int) (*SoundFont, error) {
+	sfont := C.fluid_synth_get_sfont_by_id(s.ptr, C.int(id))
+	if sfont == nil {
+		return nil, fmt.Errorf("no soundfont with ID: %d", id)
+	}
+	return &SoundFont{sfont}, nil
+}
+
+// AddSFont adds a soundfont that was created elsewhere, e.g. by another synth or a custom loader,
+// to the synth and returns the ID it was assigned.
+//
+// A synth deletes every soundfont on its list when it is closed, and fluidsynth doesn't count
+// references. A soundfont on the lists of two synths is therefore deleted twice: before closing
+// either synth, remove the soundfont with RemoveSFont from all synths but the one that closes last.
+func (s *Synth) AddSFont(sf *SoundFont) (int, error) {
+	if sf == nil || sf.ptr == nil {
+		return 0, fmt.Errorf("invalid soundfont")
+	}
+	cfont_id := C.fluid_synth_add_sfont(s.ptr, sf.ptr)
+	if cfont_id == C.FLUID_FAILED {
+		return 0, fmt.Errorf("could not add soundfont: %s", sf.Name())
+	}
+	return int(cfont_id), nil
+}
+
+// RemoveSFont removes a soundfont from the synth without deleting it. The soundfont is no
+// longer deleted when the synth is closed either, so it must stay on the list of another synth.
+func (s *Synth) RemoveSFont(sf *SoundFont) error {
+	if sf == nil || sf.ptr == nil {
+		return fmt.Errorf("invalid soundfont")
+	}
+	if C.fluid_synth_remove_sfont(s.ptr, sf.ptr) == C.FLUID_FAILED {
+		return fmt.Errorf("could not remove soundfont: %s", sf.Name())
+	}
+	return nil
+}
+
+// SetBankOffset offsets the bank numbers of a loaded soundfont
+func (s *Synth) SetBankOffset(sfid, offset int) error {
+	if C.fluid_synth_set_bank_offset(s.ptr, C.int(sfid), C.int(offset)) == C.FLUID_FAILED {
+		return fmt.Errorf("could not set bank offset of soundfont with ID: %d", sfid)
+	}
+	return nil
+}
+
+// GetBankOffset returns the bank offset of a loaded soundfont
+func (s *Synth) GetBankOffset(sfid int) (int, error) {
+	if C.fluid_synth_get_sfont_by_id(s.ptr, C.int(sfid)) == nil {
+		return 0, fmt.Errorf("no soundfont with ID: %d", sfid)
+	}
+	return int(C.fluid_synth_get_bank_offset(s.ptr, C.int(sfid))), nil
+}
+
+// GetAllBankOffsets returns the bank offset of every loaded soundfont, keyed by soundfont ID
+func (s *Synth) GetAllBankOffsets() (map[int]int, error) {
+	n := s.SFCount()
+	offsets := make(map[int]int, n)
+	for i := 0; i < n; i++ {
+		sf, err := s.GetSFont(i)
+		if err != nil {
+			return nil, err
+		}
+		offset, err := s.GetBankOffset(sf.ID())
+		if err != nil {
+			return nil, err
+		}
+		offsets[sf.ID()] = offset
+	}
+	return offsets, nil
+}
+
+// HasSoundFont returns true if at least one soundfont is loaded
+func (s *Synth) HasSoundFont() (bool, error) {
+	if s.isClosed() {
+		return false, fmt.Errorf("synth is closed")
+	}
+	return s.SFCount() > 0, nil
+}
+
+// IsSFontLoaded returns true if a soundfont with the given ID is loaded
+func (s *Synth) IsSFontLoaded(id int) (bool, error) {
+	if s.isClosed() {
+		return false, fmt.Errorf("synth is closed")
+	}
+	return C.fluid_synth_get_sfont_by_id(s.ptr, C.int(id)) != nil, nil
+}
+
+// SFLoadWithBankOffset loads a soundfont and sets its bank offset before the channels
+// select their presets again, so no channel ends up with a preset from the unshifted banks.
+func (s *Synth) SFLoadWithBankOffset(path string, resetPresets bool, bankOffset int) (int, error) {
+	sfid, err := s.SFLoad(path, false)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.SetBankOffset(sfid, bankOffset); err != nil {
+		return sfid, err
+	}
+	if resetPresets {
+		if err := s.ProgramReset(); err != nil {
+			return sfid, fmt.Errorf("failed to reset programs after loading soundfont: %s", path)
+		}
+	}
+	return sfid, nil
+}
+
+// CountPresets returns the number of presets in all loaded soundfonts, see SoundFont.CountPresets
+func (s *Synth) CountPresets() (int, error) {
+	total := 0
+	for i := 0; i < s.SFCount(); i++ {
+		sf, err := s.GetSFont(i)
+		if err != nil {
+			return 0, err
+		}
+		total += sf.CountPresets()
+	}
+	return total, nil
+}
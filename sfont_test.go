@@ -0,0 +1,97 @@
+package fluidsynth2
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestAddSFont(t *testing.T) {
+	path := testSoundFont(t)
+	owner := newTestSynth(t)
+	synth := newTestSynth(t)
+	sfid, err := owner.SFLoad(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sf, err := owner.GetSFontByID(sfid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := synth.SFCount()
+	if _, err := synth.AddSFont(sf); err != nil {
+		t.Fatal(err)
+	}
+	// the font must only be on the owner's list once the synths are closed
+	defer synth.RemoveSFont(sf)
+	if n := synth.SFCount(); n != before+1 {
+		t.Errorf("SFCount after AddSFont = %d, want %d", n, before+1)
+	}
+}
+
+func TestSFontLoadedPredicates(t *testing.T) {
+	path := testSoundFont(t)
+	synth := newTestSynth(t)
+	if has, err := synth.HasSoundFont(); err != nil || has {
+		t.Fatalf("HasSoundFont before loading = %t, %v", has, err)
+	}
+	sfid, err := synth.SFLoad(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has, err := synth.HasSoundFont(); err != nil || !has {
+		t.Errorf("HasSoundFont after loading = %t, %v", has, err)
+	}
+	if loaded, err := synth.IsSFontLoaded(sfid); err != nil || !loaded {
+		t.Errorf("IsSFontLoaded after loading = %t, %v", loaded, err)
+	}
+
+	if err := synth.SFUnload(sfid, false); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := synth.HasSoundFont(); err != nil || has {
+		t.Errorf("HasSoundFont after unloading = %t, %v", has, err)
+	}
+	if loaded, err := synth.IsSFontLoaded(sfid); err != nil || loaded {
+		t.Errorf("IsSFontLoaded after unloading = %t, %v", loaded, err)
+	}
+}
+
+func TestGetAllBankOffsets(t *testing.T) {
+	path := testSoundFont(t)
+	synth := newTestSynth(t)
+	want := make(map[int]int)
+	for _, offset := range []int{0, 128} {
+		sfid, err := synth.SFLoad(path, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := synth.SetBankOffset(sfid, offset); err != nil {
+			t.Fatal(err)
+		}
+		want[sfid] = offset
+	}
+	got, err := synth.GetAllBankOffsets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("GetAllBankOffsets = %v, want %v", got, want)
+	}
+}
+
+func TestCountPresets(t *testing.T) {
+	path := testSoundFont(t)
+	synth := newTestSynth(t)
+	if _, err := synth.SFLoad(path, false); err != nil {
+		t.Fatal(err)
+	}
+	// the test fonts are General MIDI fonts, which have at least the 128 melodic programs
+	n, err := synth.CountPresets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n < 128 {
+		t.Errorf("CountPresets = %d, want at least 128", n)
+	}
+}
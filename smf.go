@@ -0,0 +1,159 @@
+package fluidsynth2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// SET_TEMPO is the type of tempo events returned by ParseSMF, as in fluidsynth's player.
+// P1 holds the new tempo in microseconds per quarter note.
+const SET_TEMPO EventType = 0x51
+
+// TimedEvent is an event of a MIDI file at its position in ticks
+type TimedEvent struct {
+	Tick  int
+	Track int
+	Event RawMIDIEvent
+}
+
+// ParseSMF reads the events of a Standard MIDI File without playing it. fluidsynth doesn't
+// expose its MIDI file reader, so this is a small parser of its own.
+//
+// All channel messages are returned, with note-ons of velocity 0 turned into note-offs like
+// fluidsynth's player does. The only meta event included is set tempo (SET_TEMPO); text, time
+// signature and other meta events as well as sysex messages are skipped. Events of all tracks
+// are merged and sorted by tick.
+func ParseSMF(data []byte) ([]TimedEvent, error) {
+	if len(data) < 14 || string(data[0:4]) != "MThd" {
+		return nil, fmt.Errorf("invalid MIDI file: missing header")
+	}
+	hlen := int(binary.BigEndian.Uint32(data[4:8]))
+	if hlen < 6 || 8+hlen > len(data) {
+		return nil, fmt.Errorf("invalid MIDI file: bad header length %d", hlen)
+	}
+	ntracks := int(binary.BigEndian.Uint16(data[10:12]))
+
+	var events []TimedEvent
+	pos := 8 + hlen
+	for track := 0; track < ntracks && pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		clen := int(binary.BigEndian.Uint32(data[pos+4 : pos+8]))
+		pos += 8
+		if clen < 0 || pos+clen > len(data) {
+			return nil, fmt.Errorf("invalid MIDI file: chunk %q exceeds file size", id)
+		}
+		if id == "MTrk" {
+			trackEvents, err := parseSMFTrack(data[pos:pos+clen], track)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, trackEvents...)
+			track++
+		}
+		pos += clen
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Tick < events[j].Tick
+	})
+	return events, nil
+}
+
+func parseSMFTrack(data []byte, track int) ([]TimedEvent, error) {
+	var events []TimedEvent
+	var status byte
+	tick, pos := 0, 0
+
+	vlq := func() (int, error) {
+		v := 0
+		for i := 0; i < 4; i++ {
+			if pos >= len(data) {
+				break
+			}
+			b := data[pos]
+			pos++
+			v = v<<7 | int(b&0x7f)
+			if b&0x80 == 0 {
+				return v, nil
+			}
+		}
+		return 0, fmt.Errorf("invalid MIDI file: bad variable length value in track %d", track)
+	}
+
+	for pos < len(data) {
+		delta, err := vlq()
+		if err != nil {
+			return nil, err
+		}
+		tick += delta
+		if pos >= len(data) {
+			return nil, fmt.Errorf("invalid MIDI file: truncated track %d", track)
+		}
+		if data[pos]&0x80 != 0 {
+			status = data[pos]
+			pos++
+		} else if status == 0 {
+			return nil, fmt.Errorf("invalid MIDI file: running status without status byte in track %d", track)
+		}
+
+		switch {
+		case status == 0xff:
+			if pos >= len(data) {
+				return nil, fmt.Errorf("invalid MIDI file: truncated meta event in track %d", track)
+			}
+			metaType := data[pos]
+			pos++
+			n, err := vlq()
+			if err != nil {
+				return nil, err
+			}
+			if pos+n > len(data) {
+				return nil, fmt.Errorf("invalid MIDI file: truncated meta event in track %d", track)
+			}
+			meta := data[pos : pos+n]
+			pos += n
+			status = 0
+			switch {
+			case metaType == 0x2f:
+				return events, nil
+			case metaType == byte(SET_TEMPO) && n == 3:
+				tempo := int(meta[0])<<16 | int(meta[1])<<8 | int(meta[2])
+				events = append(events, TimedEvent{tick, track, RawMIDIEvent{Type: SET_TEMPO, P1: tempo}})
+			}
+		case status == 0xf0 || status == 0xf7:
+			n, err := vlq()
+			if err != nil {
+				return nil, err
+			}
+			if pos+n > len(data) {
+				return nil, fmt.Errorf("invalid MIDI file: truncated sysex in track %d", track)
+			}
+			pos += n
+			status = 0
+		case status >= 0x80 && status < 0xf0:
+			t := EventType(status & 0xf0)
+			size := 2
+			if t == PROGRAM_CHANGE || t == CHANNEL_PRESSURE {
+				size = 1
+			}
+			if pos+size > len(data) {
+				return nil, fmt.Errorf("invalid MIDI file: truncated event in track %d", track)
+			}
+			ev := RawMIDIEvent{Type: t, Channel: status & 0x0f, P1: int(data[pos])}
+			if size == 2 {
+				ev.P2 = int(data[pos+1])
+			}
+			pos += size
+			switch {
+			case t == PITCH_BEND:
+				ev.P1, ev.P2 = ev.P1|ev.P2<<7, 0
+			case t == NOTE_ON && ev.P2 == 0:
+				ev.Type = NOTE_OFF
+			}
+			events = append(events, TimedEvent{tick, track, ev})
+		default:
+			return nil, fmt.Errorf("invalid MIDI file: unsupported status %#x in track %d", status, track)
+		}
+	}
+	return events, nil
+}
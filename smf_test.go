@@ -0,0 +1,98 @@
+package fluidsynth2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+// smfFile returns a format 1 MIDI file at 480 ticks per quarter note with the given track data
+func smfFile(tracks ...[]byte) []byte {
+	var smf bytes.Buffer
+	smf.WriteString("MThd")
+	binary.Write(&smf, binary.BigEndian, uint32(6))
+	binary.Write(&smf, binary.BigEndian, []uint16{1, uint16(len(tracks)), 480})
+	for _, track := range tracks {
+		smf.WriteString("MTrk")
+		binary.Write(&smf, binary.BigEndian, uint32(len(track)))
+		smf.Write(track)
+	}
+	return smf.Bytes()
+}
+
+func TestParseSMF(t *testing.T) {
+	endOfTrack := []byte{0x00, 0xff, 0x2f, 0x00}
+	tests := []struct {
+		name string
+		data []byte
+		want []TimedEvent
+	}{
+		{"note", testSMF(1), []TimedEvent{
+			{0, 0, RawMIDIEvent{Type: NOTE_ON, P1: 60, P2: 100}},
+			{960, 0, RawMIDIEvent{Type: NOTE_OFF, P1: 60}},
+		}},
+		{"running status and note-on velocity 0", smfFile(slices.Concat(
+			[]byte{0x00, 0x93, 60, 100, 0x60, 60, 0, 0x81, 0x00, 0x83, 62, 64},
+			endOfTrack,
+		)), []TimedEvent{
+			{0, 0, RawMIDIEvent{Type: NOTE_ON, Channel: 3, P1: 60, P2: 100}},
+			{0x60, 0, RawMIDIEvent{Type: NOTE_OFF, Channel: 3, P1: 60}},
+			{0x60 + 0x80, 0, RawMIDIEvent{Type: NOTE_OFF, Channel: 3, P1: 62, P2: 64}},
+		}},
+		{"tempo, text and sysex", smfFile(slices.Concat(
+			[]byte{0x00, 0xff, 0x51, 0x03, 0x07, 0xa1, 0x20},
+			[]byte{0x00, 0xff, 0x01, 0x02, 'h', 'i'},
+			[]byte{0x00, 0xf0, 0x03, 0x7e, 0x7f, 0xf7},
+			[]byte{0x10, 0xc0, 5},
+			endOfTrack,
+		)), []TimedEvent{
+			{0, 0, RawMIDIEvent{Type: SET_TEMPO, P1: 500000}},
+			{0x10, 0, RawMIDIEvent{Type: PROGRAM_CHANGE, P1: 5}},
+		}},
+		{"pitch bend", smfFile(slices.Concat([]byte{0x00, 0xe1, 0x7f, 0x7f}, endOfTrack)), []TimedEvent{
+			{0, 0, RawMIDIEvent{Type: PITCH_BEND, Channel: 1, P1: 16383}},
+		}},
+		{"tracks merged by tick", smfFile(
+			slices.Concat([]byte{0x20, 0xb0, CC_VOLUME, 100}, endOfTrack),
+			slices.Concat([]byte{0x10, 0xd1, 40, 0x10, 0xd1, 50}, endOfTrack),
+		), []TimedEvent{
+			{0x10, 1, RawMIDIEvent{Type: CHANNEL_PRESSURE, Channel: 1, P1: 40}},
+			{0x20, 0, RawMIDIEvent{Type: CONTROL_CHANGE, P1: CC_VOLUME, P2: 100}},
+			{0x20, 1, RawMIDIEvent{Type: CHANNEL_PRESSURE, Channel: 1, P1: 50}},
+		}},
+		{"events after end of track", smfFile([]byte{0x00, 0xff, 0x2f, 0x00, 0x00, 0x90, 60, 100}), nil},
+	}
+	for _, tt := range tests {
+		got, err := ParseSMF(tt.data)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseSMFInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"no header", []byte("MTrk\x00\x00\x00\x00 and some more")},
+		{"header length", []byte("MThd\x00\x00\x00\x10\x00\x01\x00\x01\x01\xe0")},
+		{"chunk exceeds file", smfFile([]byte{0x00, 0x90, 60, 100})[:24]},
+		{"running status without status", smfFile([]byte{0x00, 60, 100})},
+		{"truncated event", smfFile([]byte{0x00, 0x90, 60})},
+		{"truncated meta event", smfFile([]byte{0x00, 0xff, 0x51, 0x03, 0x07})},
+		{"variable length value", smfFile([]byte{0xff, 0xff, 0xff, 0xff, 0x90, 60, 100})},
+		{"system common message", smfFile([]byte{0x00, 0xf2, 0x00, 0x00})},
+	}
+	for _, tt := range tests {
+		if _, err := ParseSMF(tt.data); err == nil {
+			t.Errorf("%s: ParseSMF accepted an invalid file", tt.name)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package fluidsynth2
+
+import "fmt"
+
+// ChannelState is the program and volume of a MIDI channel
+type ChannelState struct {
+	SFontID int
+	Bank    int
+	Program int
+	Volume  int
+}
+
+// SynthSnapshot is the synth's mixer state as read by Synth.Snapshot
+type SynthSnapshot struct {
+	Gain         float32
+	Polyphony    int
+	ActiveVoices int
+	CPULoad      float64
+	Channels     []ChannelState
+}
+
+// Snapshot reads the synth's mixer state in one call. It is a best-effort batch of reads, not an
+// atomic view: the lock it holds only keeps the synth from being closed meanwhile, while note,
+// gain and controller calls from other goroutines and the audio thread go on changing the state.
+// On a synth that nothing else touches it matches the individual getters.
+func (s *Synth) Snapshot() (SynthSnapshot, error) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.closed {
+		return SynthSnapshot{}, fmt.Errorf("synth is closed")
+	}
+
+	st := SynthSnapshot{
+		Gain:         s.GetGain(),
+		Polyphony:    s.GetPolyphony(),
+		ActiveVoices: s.GetActiveVoiceCount(),
+		CPULoad:      s.GetCPULoad(),
+		Channels:     make([]ChannelState, s.CountMIDIChannels()),
+	}
+	for i := range st.Channels {
+		ch := &st.Channels[i]
+		var err error
+		if ch.SFontID, ch.Bank, ch.Program, err = s.GetProgram(uint8(i)); err != nil {
+			return SynthSnapshot{}, err
+		}
+		if ch.Volume, err = s.GetCC(uint8(i), CC_VOLUME); err != nil {
+			return SynthSnapshot{}, err
+		}
+	}
+	return st, nil
+}
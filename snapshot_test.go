@@ -0,0 +1,40 @@
+package fluidsynth2
+
+import "testing"
+
+func TestSnapshot(t *testing.T) {
+	synth := newTestSynth(t)
+	if err := synth.CC(3, CC_VOLUME, 42); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := synth.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g := synth.GetGain(); snap.Gain != g {
+		t.Errorf("Gain = %f, GetGain = %f", snap.Gain, g)
+	}
+	if p := synth.GetPolyphony(); snap.Polyphony != p {
+		t.Errorf("Polyphony = %d, GetPolyphony = %d", snap.Polyphony, p)
+	}
+	if n := synth.GetActiveVoiceCount(); snap.ActiveVoices != n {
+		t.Errorf("ActiveVoices = %d, GetActiveVoiceCount = %d", snap.ActiveVoices, n)
+	}
+	if n := synth.CountMIDIChannels(); len(snap.Channels) != n {
+		t.Fatalf("%d channels, CountMIDIChannels = %d", len(snap.Channels), n)
+	}
+	for i, ch := range snap.Channels {
+		sfontID, bank, program, err := synth.GetProgram(uint8(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		volume, err := synth.GetCC(uint8(i), CC_VOLUME)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (ChannelState{sfontID, bank, program, volume}); ch != want {
+			t.Errorf("channel %d = %+v, individual reads %+v", i, ch, want)
+		}
+	}
+}
@@ -5,27 +5,162 @@ package fluidsynth2
 // #include <stdlib.h>
 import "C"
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
 type Synth struct {
-	ptr *C.fluid_synth_t
+	ptr      *C.fluid_synth_t
+	settings Settings
+	state    *synthState
+}
+
+// synthState holds binding-side state that has to be shared by all copies of a Synth
+type synthState struct {
+	mu     sync.Mutex
+	closed bool
+
+	// renderMu is held for reading by a RenderLoop while it renders a block. Close holds it
+	// for writing as well, so closed may also be read with just renderMu held.
+	renderMu sync.RWMutex
+
+	// sfLoaders are the Go soundfont loaders registered on the synth, sfOpened the loaders that
+	// opened each soundfont name, kept for reopens. sfHandle is the handle of the state passed
+	// to the loader callbacks, nil until the first loader is registered.
+	sfLoaders []SFLoader
+	sfOpened  map[string]SFLoader
+	sfHandle  unsafe.Pointer
+
+	// players are the open players of the synth, which have to be deleted before it
+	players map[*playerState]bool
+
+	defaultMods map[modKey]bool
+
+	// per-channel processing done by the binding before events reach fluidsynth
+	pressureScale map[uint8]float64
+	muted         map[uint8]bool
+	channelVoices map[uint8]*channelVoices
+
+	velocityCurves map[uint8]VelocityCurve
+
+	// tunings are the tunings activated on channels, fluidsynth has no getter for them
+	tunings map[uint8]TuningId
+
+	cpuLoad cpuLoadHistory
+
+	reverbBypassed bool
+	chorusBypassed bool
+
+	deterministic bool
+
+	// wet is the master dry/wet amount, wetLevels the effect levels it scales (nil while fully wet)
+	wet       float64
+	wetLevels []effectLevels
+
+	// renderGuard makes concurrent render calls fail instead of interleaving their output
+	renderGuard atomic.Bool
+	rendering   atomic.Bool
+
+	// dither24 enables dither for WriteS24, ditherState is its noise generator's state
+	dither24    atomic.Bool
+	ditherState atomic.Uint32
 }
 
 func NewSynth(settings Settings) Synth {
-	return Synth{C.new_fluid_synth(settings.ptr)}
+	installLogFunction()
+	settings.retain()
+	return Synth{
+		ptr:      C.new_fluid_synth(settings.ptr),
+		settings: settings,
+		state:    &synthState{wet: 1, deterministic: settings.IsDeterministic()},
+	}
 }
 
+// Close deletes the synth. fluidsynth requires a synth's players to be deleted first, so players
+// created on the synth that are still open are closed as well.
 func (s *Synth) Close() {
-	C.delete_fluid_synth(s.ptr)
+	// the players are closed without holding the synth's locks, see playerState.close
+	for _, st := range s.state.takePlayers() {
+		st.close()
+	}
+	s.state.renderMu.Lock()
+	defer s.state.renderMu.Unlock()
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if !s.state.closed {
+		C.delete_fluid_synth(s.ptr)
+		s.state.closed = true
+		s.settings.release()
+		s.state.dropSFLoaders()
+	}
+}
+
+// addPlayer and removePlayer track the open players of the synth, takePlayers returns them
+// and stops the tracking before the synth is closed
+func (st *synthState) addPlayer(p *playerState) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return
+	}
+	if st.players == nil {
+		st.players = make(map[*playerState]bool)
+	}
+	st.players[p] = true
+}
+
+func (st *synthState) removePlayer(p *playerState) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.players, p)
+}
+
+func (st *synthState) takePlayers() []*playerState {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	players := make([]*playerState, 0, len(st.players))
+	for p := range st.players {
+		players = append(players, p)
+	}
+	st.players = nil
+	return players
+}
+
+func (s *Synth) isClosed() bool {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.closed
+}
+
+// GetSettings returns the settings the synth was created with, or nil if they have been closed
+func (s *Synth) GetSettings() *Settings {
+	if !s.settings.isOpen() {
+		return nil
+	}
+	settings := s.settings
+	return &settings
+}
+
+// GetSampleRate returns the sample rate from the "synth.sample-rate" setting
+func (s *Synth) GetSampleRate() (float64, error) {
+	var rate float64
+	if !s.settings.GetNum("synth.sample-rate", &rate) {
+		return 0, fmt.Errorf("failed to read synth.sample-rate")
+	}
+	return rate, nil
 }
 
 func (s *Synth) SFLoad(path string, resetPresets bool) (int, error) {
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
 	creset := cbool(resetPresets)
-	cfont_id := C.fluid_synth_sfload(s.ptr, cpath, creset)
+	cfont_id := sfload(s.ptr, s.sfLoaderHandle(), cpath, creset)
 	if cfont_id == C.FLUID_FAILED {
 		return 0, fmt.Errorf("could not load soundfont: %s", path)
 	}
@@ -33,7 +168,7 @@ func (s *Synth) SFLoad(path string, resetPresets bool) (int, error) {
 }
 
 func (s *Synth) SFReload(sfid int) (int, error) {
-	cfont_id := C.fluid_synth_sfreload(s.ptr, C.int(sfid))
+	cfont_id := sfreload(s.ptr, s.sfLoaderHandle(), C.int(sfid))
 	if cfont_id == C.FLUID_FAILED {
 		return 0, fmt.Errorf("could not reload soundfont with ID: %d", sfid)
 	}
@@ -49,6 +184,17 @@ func (s *Synth) SFUnload(sfid int, reset bool) error {
 }
 
 func (s *Synth) NoteOn(channel, note, velocity uint8) error {
+	if velocity > 0 && s.isMuted(channel) {
+		return nil
+	}
+	velocity = s.applyVelocityCurve(channel, velocity)
+	if velocity == 0 {
+		s.stopNote(channel, note)
+	} else {
+		for _, old := range s.startNote(channel, note) {
+			s.NoteOff(channel, old)
+		}
+	}
 	result := C.fluid_synth_noteon(s.ptr, C.int(channel), C.int(note), C.int(velocity))
 	if result == C.FLUID_FAILED {
 		return fmt.Errorf("failed to turn note on: channel=%d, note=%d, velocity=%d", channel, note, velocity)
@@ -57,13 +203,93 @@ func (s *Synth) NoteOn(channel, note, velocity uint8) error {
 }
 
 func (s *Synth) NoteOff(channel, note uint8) {
+	s.stopNote(channel, note)
 	C.fluid_synth_noteoff(s.ptr, C.int(channel), C.int(note))
 }
 
+// NoteOffVel sends a note-off with a release velocity. fluid_synth_noteoff has no velocity
+// parameter, so a MIDI event is handed to the synth instead. Whether the velocity has any effect
+// depends on fluidsynth and the soundfont: SoundFont 2 has no release velocity modulator source,
+// and current fluidsynth versions drop it when handling the event.
+func (s *Synth) NoteOffVel(channel, note, velocity uint8) error {
+	if note > MAX_MIDI_NOTE {
+		return fmt.Errorf("invalid note: %d", note)
+	}
+	if velocity > MAX_MIDI_VELOCITY {
+		return fmt.Errorf("invalid velocity: %d", velocity)
+	}
+	ev := C.new_fluid_midi_event()
+	if ev == nil {
+		return fmt.Errorf("failed to create MIDI event")
+	}
+	defer C.delete_fluid_midi_event(ev)
+	C.fluid_midi_event_set_type(ev, C.int(NOTE_OFF))
+	C.fluid_midi_event_set_channel(ev, C.int(channel))
+	C.fluid_midi_event_set_key(ev, C.int(note))
+	C.fluid_midi_event_set_velocity(ev, C.int(velocity))
+	if C.fluid_synth_handle_midi_event(unsafe.Pointer(s.ptr), ev) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to send note off: channel=%d, note=%d", channel, note)
+	}
+	s.stopNote(channel, note)
+	return nil
+}
+
+// PlayNote plays a note for the duration d and blocks until it has been released.
+// If ctx is done before d has passed the note is released early and ctx.Err() is returned.
+func (s *Synth) PlayNote(ctx context.Context, channel, note, velocity uint8, d time.Duration) error {
+	if err := s.NoteOn(channel, note, velocity); err != nil {
+		return err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		s.NoteOff(channel, note)
+		return nil
+	case <-ctx.Done():
+		s.NoteOff(channel, note)
+		return ctx.Err()
+	}
+}
+
+// NoteOnChord turns on several notes at once. Invalid notes don't stop the remaining
+// notes from being played, all errors are returned together.
+func (s *Synth) NoteOnChord(channel uint8, notes []uint8, velocity uint8) error {
+	var errs []error
+	for _, note := range notes {
+		if note > MAX_MIDI_NOTE {
+			errs = append(errs, fmt.Errorf("invalid note: %d", note))
+			continue
+		}
+		if err := s.NoteOn(channel, note, velocity); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NoteOffChord turns off several notes at once
+func (s *Synth) NoteOffChord(channel uint8, notes []uint8) error {
+	var errs []error
+	for _, note := range notes {
+		if note > MAX_MIDI_NOTE {
+			errs = append(errs, fmt.Errorf("invalid note: %d", note))
+			continue
+		}
+		s.NoteOff(channel, note)
+	}
+	return errors.Join(errs...)
+}
+
 func (s *Synth) ProgramChange(channel, program uint8) {
 	C.fluid_synth_program_change(s.ptr, C.int(channel), C.int(program))
 }
 
+// ProgramReset makes every channel select its preset again, e.g. after soundfonts or bank offsets changed
+func (s *Synth) ProgramReset() error {
+	return fluidStatus(C.fluid_synth_program_reset(s.ptr))
+}
+
 func (s *Synth) GetGain() float32 {
 	return float32(C.fluid_synth_get_gain(s.ptr))
 }
@@ -72,6 +298,64 @@ func (s *Synth) SetGain(g float32) {
 	C.fluid_synth_set_gain(s.ptr, C.float(g))
 }
 
+// SetDeterministic fails unless the synth already is in the requested mode: the settings
+// involved are only read when a synth is created, so deterministic rendering has to be set up
+// with Settings.SetDeterministic before NewSynth.
+func (s *Synth) SetDeterministic(on bool) error {
+	if on != s.IsDeterministic() {
+		return fmt.Errorf("deterministic mode can only be set on the Settings before creating the synth")
+	}
+	return nil
+}
+
+// IsDeterministic returns true if the synth was created from deterministic Settings
+func (s *Synth) IsDeterministic() bool {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.deterministic
+}
+
+// GetPolyphony returns the maximum number of voices
+func (s *Synth) GetPolyphony() int {
+	return int(C.fluid_synth_get_polyphony(s.ptr))
+}
+
+// CountMIDIChannels returns the number of MIDI channels of the synth
+func (s *Synth) CountMIDIChannels() int {
+	return int(C.fluid_synth_count_midi_channels(s.ptr))
+}
+
+// GetCPULoad returns the CPU load estimated by fluidsynth, in percent
+func (s *Synth) GetCPULoad() float64 {
+	return float64(C.fluid_synth_get_cpu_load(s.ptr))
+}
+
+const maxGain = 10.0
+
+// SetGainDB sets the gain in decibels relative to a linear gain of 1.0,
+// using gain = 10^(db/20). The result is clamped to fluidsynth's 0.0-10.0 range (about +20 dB).
+func (s *Synth) SetGainDB(db float64) error {
+	if math.IsNaN(db) {
+		return fmt.Errorf("invalid gain: %f dB", db)
+	}
+	g := math.Pow(10, db/20)
+	if g > maxGain {
+		g = maxGain
+	}
+	s.SetGain(float32(g))
+	return nil
+}
+
+// GetGainDB returns the gain in decibels relative to a linear gain of 1.0, using db = 20*log10(gain).
+// A gain of 0.0 is reported as negative infinity.
+func (s *Synth) GetGainDB() (float64, error) {
+	g := float64(s.GetGain())
+	if g < 0 {
+		return 0, fmt.Errorf("invalid gain: %f", g)
+	}
+	return 20 * math.Log10(g), nil
+}
+
 /*
 	WriteS16 synthesizes signed 16-bit samples. It will fill as much of the provided
 
@@ -81,6 +365,21 @@ slices as it can without overflowing 'left' or 'right'. For interleaved stereo,
 	synth.WriteS16(samples, samples[1:], 2, 2)
 */
 func (s *Synth) WriteS16(left, right []int16, lstride, rstride int) error {
+	release, err := s.guardRender()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return s.writeS16(left, right, lstride, rstride)
+}
+
+// WriteS16Unlocked is WriteS16 without the render guard check, for hot loops between BeginRender
+// and EndRender. Calling it concurrently with any other render call is a data race.
+func (s *Synth) WriteS16Unlocked(left, right []int16, lstride, rstride int) error {
+	return s.writeS16(left, right, lstride, rstride)
+}
+
+func (s *Synth) writeS16(left, right []int16, lstride, rstride int) error {
 	nframes := (len(left) + lstride - 1) / lstride
 	rframes := (len(right) + rstride - 1) / rstride
 	if rframes < nframes {
@@ -94,6 +393,21 @@ func (s *Synth) WriteS16(left, right []int16, lstride, rstride int) error {
 }
 
 func (s *Synth) WriteFloat(left, right []float32, lstride, rstride int) error {
+	release, err := s.guardRender()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return s.writeFloat(left, right, lstride, rstride)
+}
+
+// WriteFloatUnlocked is WriteFloat without the render guard check, for hot loops between BeginRender
+// and EndRender. Calling it concurrently with any other render call is a data race.
+func (s *Synth) WriteFloatUnlocked(left, right []float32, lstride, rstride int) error {
+	return s.writeFloat(left, right, lstride, rstride)
+}
+
+func (s *Synth) writeFloat(left, right []float32, lstride, rstride int) error {
 	nframes := (len(left) + lstride - 1) / lstride
 	rframes := (len(right) + rstride - 1) / rstride
 	if rframes < nframes {
@@ -106,6 +420,112 @@ func (s *Synth) WriteFloat(left, right []float32, lstride, rstride int) error {
 	return nil
 }
 
+// WriteS16Interleaved synthesizes signed 16-bit samples into buf as interleaved
+// left/right pairs and returns the number of frames written, len(buf)/2.
+func (s *Synth) WriteS16Interleaved(buf []int16) (frames int, err error) {
+	frames = len(buf) / 2
+	if frames == 0 {
+		return 0, fmt.Errorf("no frames to write")
+	}
+	release, err := s.guardRender()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	C.fluid_synth_write_s16(s.ptr, C.int(frames), unsafe.Pointer(&buf[0]), 0, 2, unsafe.Pointer(&buf[0]), 1, 2)
+	return frames, nil
+}
+
+// WriteFloatInterleaved synthesizes float samples into buf as interleaved
+// left/right pairs and returns the number of frames written, len(buf)/2.
+func (s *Synth) WriteFloatInterleaved(buf []float32) (frames int, err error) {
+	frames = len(buf) / 2
+	if frames == 0 {
+		return 0, fmt.Errorf("no frames to write")
+	}
+	release, err := s.guardRender()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	C.fluid_synth_write_float(s.ptr, C.int(frames), unsafe.Pointer(&buf[0]), 0, 2, unsafe.Pointer(&buf[0]), 1, 2)
+	return frames, nil
+}
+
+var floatRenderPool = sync.Pool{
+	New: func() any { return new([]float32) },
+}
+
+// SetS24Dither enables dither for WriteS24. It is off by default: the rounding error of 24-bit
+// output lies far below the synth's own noise floor, so dither only pays off if the output is
+// processed further at 24 bits, e.g. attenuated. With dither on, triangular noise of up to ±1 LSB
+// is added before rounding. The noise comes from a generator with a fixed seed, so renders of
+// a fresh synth stay reproducible (see Settings.SetDeterministic).
+func (s *Synth) SetS24Dither(on bool) {
+	s.state.dither24.Store(on)
+}
+
+// WriteS24 synthesizes frames frames into buf as interleaved left/right pairs of packed 24-bit
+// little-endian samples, 6 bytes per frame. The synth renders floats, which are rounded to
+// 24 bits, see SetS24Dither. The float buffer is taken from a pool, so repeated calls don't
+// allocate.
+func (s *Synth) WriteS24(buf []byte, frames int) error {
+	if frames <= 0 {
+		return fmt.Errorf("no frames to write")
+	}
+	if len(buf) < frames*6 {
+		return fmt.Errorf("buffer too small for %d frames: %d bytes", frames, len(buf))
+	}
+	bp := floatRenderPool.Get().(*[]float32)
+	defer floatRenderPool.Put(bp)
+	if cap(*bp) < 2*frames {
+		*bp = make([]float32, 2*frames)
+	}
+	samples := (*bp)[:2*frames]
+	if _, err := s.WriteFloatInterleaved(samples); err != nil {
+		return err
+	}
+
+	const maxS24 = 1<<23 - 1
+	dither := s.state.dither24.Load()
+	var state uint32
+	if dither {
+		state = s.state.ditherState.Load()
+	}
+	for i, f := range samples {
+		x := float64(f) * maxS24
+		if dither {
+			var noise float64
+			noise, state = tpdfNoise(state)
+			x += noise
+		}
+		v := int32(math.Round(math.Max(-maxS24, math.Min(maxS24, x))))
+		buf[3*i] = byte(v)
+		buf[3*i+1] = byte(v >> 8)
+		buf[3*i+2] = byte(v >> 16)
+	}
+	if dither {
+		s.state.ditherState.Store(state)
+	}
+	return nil
+}
+
+// tpdfNoise returns triangular noise in (-1, 1) and the next state of the xorshift generator
+// it is drawn from. The zero state, which xorshift can't leave, stands for the seed.
+func tpdfNoise(state uint32) (float64, uint32) {
+	if state == 0 {
+		state = 0x9e3779b9
+	}
+	var r [2]uint32
+	for i := range r {
+		state ^= state << 13
+		state ^= state >> 17
+		state ^= state << 5
+		r[i] = state
+	}
+	return (float64(r[0]) - float64(r[1])) / (1 << 32), state
+}
+
 type TuningId struct {
 	Bank, Program uint8
 }
@@ -119,5 +539,37 @@ func (s *Synth) ActivateKeyTuning(id TuningId, name string, tuning [128]float64,
 
 /* ActivateTuning switches a midi channel onto the specified tuning bank/program */
 func (s *Synth) ActivateTuning(channel uint8, id TuningId, apply bool) {
-	C.fluid_synth_activate_tuning(s.ptr, C.int(channel), C.int(id.Bank), C.int(id.Program), cbool(apply))
+	if C.fluid_synth_activate_tuning(s.ptr, C.int(channel), C.int(id.Bank), C.int(id.Program), cbool(apply)) == C.FLUID_FAILED {
+		return
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.tunings == nil {
+		s.state.tunings = make(map[uint8]TuningId)
+	}
+	s.state.tunings[channel] = id
+}
+
+// DeactivateTuning switches a midi channel back to the default equal temperament
+func (s *Synth) DeactivateTuning(channel uint8, apply bool) error {
+	if C.fluid_synth_deactivate_tuning(s.ptr, C.int(channel), cbool(apply)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to deactivate tuning: channel=%d", channel)
+	}
+	s.state.mu.Lock()
+	delete(s.state.tunings, channel)
+	s.state.mu.Unlock()
+	return nil
+}
+
+// GetChannelTuning returns the tuning of a channel and whether one is active. fluidsynth has no
+// getter for it, so the binding records the calls to ActivateTuning and DeactivateTuning;
+// tunings selected by MIDI tuning standard messages sent with Sysex are not seen.
+func (s *Synth) GetChannelTuning(channel uint8) (TuningId, bool, error) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if s.state.closed {
+		return TuningId{}, false, fmt.Errorf("synth is closed")
+	}
+	id, ok := s.state.tunings[channel]
+	return id, ok, nil
 }
@@ -0,0 +1,210 @@
+package fluidsynth2
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+// newTestSynth returns a synth on fresh settings, closed when the test ends
+func newTestSynth(t testing.TB) Synth {
+	t.Helper()
+	settings := NewSettings()
+	synth := NewSynth(settings)
+	t.Cleanup(func() {
+		synth.Close()
+		settings.Close()
+	})
+	return synth
+}
+
+// testSoundFont returns the path of a soundfont to render with, set with FLUIDSYNTH_TEST_SF2 or
+// found in the usual install locations, and skips the test if there is none
+func testSoundFont(t testing.TB) string {
+	t.Helper()
+	for _, path := range []string{
+		os.Getenv("FLUIDSYNTH_TEST_SF2"),
+		"/usr/share/sounds/sf2/FluidR3_GM.sf2",
+		"/usr/share/soundfonts/FluidR3_GM.sf2",
+		"/usr/share/soundfonts/default.sf2",
+	} {
+		if _, err := os.Stat(path); path != "" && err == nil {
+			return path
+		}
+	}
+	t.Skip("no soundfont found, set FLUIDSYNTH_TEST_SF2")
+	return ""
+}
+
+func TestDeterministicRender(t *testing.T) {
+	sf := testSoundFont(t)
+	render := func() []float32 {
+		settings := NewSettings()
+		defer settings.Close()
+		if err := settings.SetDeterministic(true); err != nil {
+			t.Fatal(err)
+		}
+		synth := NewSynth(settings)
+		defer synth.Close()
+		if !synth.IsDeterministic() {
+			t.Error("synth created from deterministic settings isn't deterministic")
+		}
+		if err := synth.SetDeterministic(false); err == nil {
+			t.Error("SetDeterministic changed the mode of a live synth")
+		}
+		if _, err := synth.SFLoad(sf, true); err != nil {
+			t.Fatal(err)
+		}
+		player := NewPlayer(synth)
+		defer player.Close()
+		if err := player.AddMem(testSMF(1)); err != nil {
+			t.Fatal(err)
+		}
+		if err := player.Play(); err != nil {
+			t.Fatal(err)
+		}
+		out := make([]float32, 2*44100)
+		for i := 0; i < len(out); i += 2 * offlineBlockFrames {
+			block := out[i:min(i+2*offlineBlockFrames, len(out))]
+			if err := synth.WriteFloat(block, block[1:], 2, 2); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return out
+	}
+	first, second := render(), render()
+	if i := slices.IndexFunc(first, func(v float32) bool { return v != 0 }); i < 0 {
+		t.Fatal("rendered silence")
+	}
+	if !slices.Equal(first, second) {
+		t.Error("deterministic renders differ")
+	}
+}
+
+func TestWriteUnlockedMatchesGuarded(t *testing.T) {
+	sf := testSoundFont(t)
+	render := func(unlocked bool) ([]int16, []float32) {
+		synth := newTestSynth(t)
+		if _, err := synth.SFLoad(sf, true); err != nil {
+			t.Fatal(err)
+		}
+		synth.SetRenderGuard(true)
+		if err := synth.NoteOn(0, 60, 100); err != nil {
+			t.Fatal(err)
+		}
+		s16 := make([]int16, 2*4096)
+		f32 := make([]float32, 2*4096)
+		if unlocked {
+			if err := synth.BeginRender(); err != nil {
+				t.Fatal(err)
+			}
+			defer synth.EndRender()
+		}
+		for i := 0; i < len(s16); i += 2 * offlineBlockFrames {
+			b16 := s16[i : i+2*offlineBlockFrames]
+			b32 := f32[i : i+2*offlineBlockFrames]
+			var err error
+			if unlocked {
+				err = errors.Join(synth.WriteS16Unlocked(b16, b16[1:], 2, 2), synth.WriteFloatUnlocked(b32, b32[1:], 2, 2))
+			} else {
+				err = errors.Join(synth.WriteS16(b16, b16[1:], 2, 2), synth.WriteFloat(b32, b32[1:], 2, 2))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return s16, f32
+	}
+	guarded16, guarded32 := render(false)
+	unlocked16, unlocked32 := render(true)
+	if !slices.Equal(guarded16, unlocked16) {
+		t.Error("WriteS16Unlocked output differs from WriteS16")
+	}
+	if !slices.Equal(guarded32, unlocked32) {
+		t.Error("WriteFloatUnlocked output differs from WriteFloat")
+	}
+}
+
+func BenchmarkWriteS16Guarded(b *testing.B) {
+	synth := newTestSynth(b)
+	synth.SetRenderGuard(true)
+	buf := make([]int16, 2*fluidBlockFrames)
+	for i := 0; i < b.N; i++ {
+		if err := synth.WriteS16(buf, buf[1:], 2, 2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteS16Unlocked(b *testing.B) {
+	synth := newTestSynth(b)
+	if err := synth.BeginRender(); err != nil {
+		b.Fatal(err)
+	}
+	defer synth.EndRender()
+	buf := make([]int16, 2*fluidBlockFrames)
+	for i := 0; i < b.N; i++ {
+		if err := synth.WriteS16Unlocked(buf, buf[1:], 2, 2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestWriteS24(t *testing.T) {
+	sf := testSoundFont(t)
+	synth := newTestSynth(t)
+	if _, err := synth.SFLoad(sf, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := synth.NoteOn(0, 60, 100); err != nil {
+		t.Fatal(err)
+	}
+	const frames = 4096
+	buf := make([]byte, frames*2*3)
+	if err := synth.WriteS24(buf[:len(buf)-1], frames); err == nil {
+		t.Error("WriteS24 accepted a buffer shorter than frames*2*3 bytes")
+	}
+	if err := synth.WriteS24(buf, frames); err != nil {
+		t.Fatal(err)
+	}
+	if slices.IndexFunc(buf, func(b byte) bool { return b != 0 }) < 0 {
+		t.Error("rendered silence")
+	}
+}
+
+func TestWriteS24Dither(t *testing.T) {
+	render := func() []byte {
+		synth := newTestSynth(t)
+		synth.SetS24Dither(true)
+		buf := make([]byte, 2*3*offlineBlockFrames)
+		for i := 0; i < 2; i++ {
+			if err := synth.WriteS24(buf, offlineBlockFrames); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return buf
+	}
+	first, second := render(), render()
+	// the synth renders silence, so the dither is all there is
+	for i := 0; i < len(first); i += 3 {
+		v := int32(first[i]) | int32(first[i+1])<<8 | int32(int8(first[i+2]))<<16
+		if v < -1 || v > 1 {
+			t.Fatalf("sample %d = %d, dither exceeds 1 LSB", i/3, v)
+		}
+	}
+	if !slices.Equal(first, second) {
+		t.Error("dithered renders of fresh synths differ")
+	}
+}
+
+func BenchmarkWriteS24(b *testing.B) {
+	synth := newTestSynth(b)
+	buf := make([]byte, 2*3*offlineBlockFrames)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := synth.WriteS24(buf, offlineBlockFrames); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "fmt"
+
+// Sysex sends a system exclusive message to the synth.
+// data must not include the leading 0xF0 and trailing 0xF7 bytes.
+func (s *Synth) Sysex(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty sysex message")
+	}
+	cdata := C.CBytes(data)
+	defer C.free(cdata)
+	if C.fluid_synth_sysex(s.ptr, (*C.char)(cdata), C.int(len(data)), nil, nil, nil, 0) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to send sysex message: % X", data)
+	}
+	return nil
+}
+
+var (
+	// F0 7E 7F 09 01 F7
+	sysexGMOn = []byte{0x7e, 0x7f, 0x09, 0x01}
+	// F0 41 10 42 12 40 00 7F 00 41 F7
+	sysexGSReset = []byte{0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7f, 0x00, 0x41}
+	// F0 43 10 4C 00 00 7E 00 F7
+	sysexXGOn = []byte{0x43, 0x10, 0x4c, 0x00, 0x00, 0x7e, 0x00}
+)
+
+// ResetGM sends the General MIDI System On message: F0 7E 7F 09 01 F7
+func (s *Synth) ResetGM() error {
+	return s.Sysex(sysexGMOn)
+}
+
+// ResetGS sends the Roland GS Reset message: F0 41 10 42 12 40 00 7F 00 41 F7
+func (s *Synth) ResetGS() error {
+	return s.Sysex(sysexGSReset)
+}
+
+// ResetXG sends the Yamaha XG System On message: F0 43 10 4C 00 00 7E 00 F7
+func (s *Synth) ResetXG() error {
+	return s.Sysex(sysexXGOn)
+}
@@ -0,0 +1,23 @@
+package fluidsynth2
+
+import "testing"
+
+func TestResetGM(t *testing.T) {
+	sf := testSoundFont(t)
+	synth := newTestSynth(t)
+	if _, err := synth.SFLoad(sf, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := synth.ResetGM(); err != nil {
+		t.Fatal(err)
+	}
+	if _, bank, _, err := synth.GetProgram(0); err != nil || bank != 0 {
+		t.Fatalf("channel 0 is on bank %d after ResetGM (%v), want 0", bank, err)
+	}
+	if err := synth.NoteOn(0, 60, 100); err != nil {
+		t.Fatal(err)
+	}
+	if synth.GetActiveVoiceCount() == 0 {
+		t.Error("note on the default bank doesn't sound after ResetGM")
+	}
+}
@@ -0,0 +1,71 @@
+package fluidsynth2
+
+import "math"
+
+// VelocityCurve maps the velocity of a note-on (1-127) to the velocity played by the synth
+type VelocityCurve func(velocity uint8) uint8
+
+// LinearVelocity plays velocities unchanged
+func LinearVelocity() VelocityCurve {
+	return func(v uint8) uint8 { return v }
+}
+
+// ExponentialVelocity maps v to 127*(v/127)^exp. An exp above 1 makes the response softer,
+// so that more force is needed for loud notes, below 1 it makes it harder.
+func ExponentialVelocity(exp float64) VelocityCurve {
+	return func(v uint8) uint8 {
+		return velocityFromFloat(MAX_MIDI_VELOCITY * math.Pow(float64(v)/MAX_MIDI_VELOCITY, exp))
+	}
+}
+
+// LogarithmicVelocity maps v to 127*log(1+k*v/127)/log(1+k), which boosts soft notes more the
+// larger k is
+func LogarithmicVelocity(k float64) VelocityCurve {
+	return func(v uint8) uint8 {
+		return velocityFromFloat(MAX_MIDI_VELOCITY * math.Log1p(k*float64(v)/MAX_MIDI_VELOCITY) / math.Log1p(k))
+	}
+}
+
+// FixedVelocity plays every note at velocity, like an organ
+func FixedVelocity(velocity uint8) VelocityCurve {
+	return func(uint8) uint8 { return velocity }
+}
+
+// velocityFromFloat rounds a velocity and clamps it to 1-127, so that a curve never turns a
+// note-on into a note-off
+func velocityFromFloat(v float64) uint8 {
+	if math.IsNaN(v) {
+		return 1
+	}
+	return uint8(math.Max(1, math.Min(MAX_MIDI_VELOCITY, math.Round(v))))
+}
+
+// SetVelocityCurve sets the curve applied to the velocity of notes played on a channel with
+// NoteOn and SendEvents, nil removes it. The curve is applied by the binding; notes played by a
+// Player are not affected.
+func (s *Synth) SetVelocityCurve(channel uint8, curve VelocityCurve) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if curve == nil {
+		delete(s.state.velocityCurves, channel)
+		return
+	}
+	if s.state.velocityCurves == nil {
+		s.state.velocityCurves = make(map[uint8]VelocityCurve)
+	}
+	s.state.velocityCurves[channel] = curve
+}
+
+// applyVelocityCurve returns the velocity to play a note-on with
+func (s *Synth) applyVelocityCurve(channel, velocity uint8) uint8 {
+	if velocity == 0 {
+		return 0
+	}
+	s.state.mu.Lock()
+	curve := s.state.velocityCurves[channel]
+	s.state.mu.Unlock()
+	if curve == nil {
+		return velocity
+	}
+	return min(max(curve(velocity), 1), MAX_MIDI_VELOCITY)
+}
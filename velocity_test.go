@@ -0,0 +1,71 @@
+package fluidsynth2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVelocityCurves(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve VelocityCurve
+		in    []uint8
+		want  []uint8
+	}{
+		{"linear", LinearVelocity(), []uint8{1, 64, 127}, []uint8{1, 64, 127}},
+		{"exponential 2", ExponentialVelocity(2), []uint8{1, 64, 127}, []uint8{1, 32, 127}},
+		{"exponential 0.5", ExponentialVelocity(0.5), []uint8{1, 64, 127}, []uint8{11, 90, 127}},
+		{"logarithmic 10", LogarithmicVelocity(10), []uint8{1, 64, 127}, []uint8{4, 95, 127}},
+		{"fixed", FixedVelocity(100), []uint8{1, 64, 127}, []uint8{100, 100, 100}},
+	}
+	for _, tt := range tests {
+		for i, v := range tt.in {
+			if got := tt.curve(v); got != tt.want[i] {
+				t.Errorf("%s: %d maps to %d, want %d", tt.name, v, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestVelocityFromFloat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want uint8
+	}{
+		{math.NaN(), 1},
+		{-5, 1},
+		{0.4, 1},
+		{63.5, 64},
+		{126.6, 127},
+		{200, 127},
+		{math.Inf(1), 127},
+	}
+	for _, tt := range tests {
+		if got := velocityFromFloat(tt.in); got != tt.want {
+			t.Errorf("velocityFromFloat(%f) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestApplyVelocityCurve(t *testing.T) {
+	synth := newTestSynth(t)
+	synth.SetVelocityCurve(1, FixedVelocity(0))
+	synth.SetVelocityCurve(2, FixedVelocity(200))
+	tests := []struct {
+		channel, in, want uint8
+	}{
+		{0, 50, 50},
+		{1, 50, 1},
+		{1, 0, 0},
+		{2, 50, 127},
+	}
+	for _, tt := range tests {
+		if got := synth.applyVelocityCurve(tt.channel, tt.in); got != tt.want {
+			t.Errorf("channel %d: velocity %d plays at %d, want %d", tt.channel, tt.in, got, tt.want)
+		}
+	}
+	synth.SetVelocityCurve(1, nil)
+	if got := synth.applyVelocityCurve(1, 50); got != 50 {
+		t.Errorf("removed curve still applies: velocity 50 plays at %d", got)
+	}
+}
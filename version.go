@@ -0,0 +1,58 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "slices"
+
+// Version returns the version of the fluidsynth library in use
+func Version() (major, minor, micro int) {
+	var cmajor, cminor, cmicro C.int
+	C.fluid_version(&cmajor, &cminor, &cmicro)
+	return int(cmajor), int(cminor), int(cmicro)
+}
+
+// VersionString returns the version of the fluidsynth library in use, e.g. "2.3.4"
+func VersionString() string {
+	return C.GoString(C.fluid_version_str())
+}
+
+// versionAtLeast returns true if the fluidsynth library is at least major.minor
+func versionAtLeast(major, minor int) bool {
+	maj, min, _ := Version()
+	return maj > major || maj == major && min >= minor
+}
+
+type Feature int
+
+const (
+	// FeatureEffectsGroups is per-group reverb and chorus control, fluidsynth 2.2+
+	FeatureEffectsGroups Feature = iota
+	// FeatureBasicChannels is MIDI basic channel (omni/poly/mono mode) support, fluidsynth 2.0+
+	FeatureBasicChannels
+	// FeatureLADSPA is the LADSPA effects unit, if fluidsynth was built with it
+	FeatureLADSPA
+	// FeatureOggOutput is Ogg Vorbis file output, if fluidsynth was built with libsndfile and Ogg support
+	FeatureOggOutput
+)
+
+// HasFeature returns true if the fluidsynth library in use supports a feature. Version dependent
+// features are checked with VersionString, build options by looking at the available settings.
+func HasFeature(f Feature) bool {
+	switch f {
+	case FeatureEffectsGroups:
+		return versionAtLeast(2, 2)
+	case FeatureBasicChannels:
+		return versionAtLeast(2, 0)
+	case FeatureLADSPA, FeatureOggOutput:
+		settings := NewSettings()
+		defer settings.Close()
+		if f == FeatureLADSPA {
+			return settings.GetType("synth.ladspa.active") != SETTING_TYPE_NONE
+		}
+		return slices.Contains(settings.GetOptions("audio.file.type"), "oga")
+	default:
+		return false
+	}
+}
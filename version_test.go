@@ -0,0 +1,20 @@
+package fluidsynth2
+
+import "testing"
+
+func TestHasFeature(t *testing.T) {
+	major, minor, _ := Version()
+	tests := []struct {
+		feature Feature
+		want    bool
+	}{
+		{FeatureEffectsGroups, major > 2 || major == 2 && minor >= 2},
+		{FeatureBasicChannels, major >= 2},
+		{Feature(-1), false},
+	}
+	for _, tt := range tests {
+		if got := HasFeature(tt.feature); got != tt.want {
+			t.Errorf("HasFeature(%d) with fluidsynth %s = %t, want %t", tt.feature, VersionString(), got, tt.want)
+		}
+	}
+}
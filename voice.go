@@ -0,0 +1,78 @@
+package fluidsynth2
+
+// #cgo pkg-config: fluidsynth
+// #include <fluidsynth.h>
+// #include <stdlib.h>
+import "C"
+import "fmt"
+
+// VoiceInfo describes a single voice of the synthesizer
+type VoiceInfo struct {
+	ID       uint
+	Channel  int
+	Key      int
+	Velocity int
+	Playing  bool
+}
+
+// GetActiveVoiceCount returns the number of voices that are currently sounding
+func (s *Synth) GetActiveVoiceCount() int {
+	return int(C.fluid_synth_get_active_voice_count(s.ptr))
+}
+
+// GetActiveVoices returns information about the voices that are currently playing.
+//
+// fluid_synth_get_voicelist can filter voices by the ID they were started with; this method
+// passes -1 so that every playing voice is returned. The list is a snapshot: fluidsynth may
+// start or release voices from the audio thread while it is being read, so it should only be
+// used for monitoring purposes.
+func (s *Synth) GetActiveVoices() ([]VoiceInfo, error) {
+	polyphony := int(C.fluid_synth_get_polyphony(s.ptr))
+	if polyphony <= 0 {
+		return nil, fmt.Errorf("invalid polyphony: %d", polyphony)
+	}
+	buf := make([]*C.fluid_voice_t, polyphony)
+	C.fluid_synth_get_voicelist(s.ptr, &buf[0], C.int(polyphony), -1)
+
+	voices := make([]VoiceInfo, 0, polyphony)
+	for _, v := range buf {
+		if v == nil {
+			break
+		}
+		voices = append(voices, VoiceInfo{
+			ID:       uint(C.fluid_voice_get_id(v)),
+			Channel:  int(C.fluid_voice_get_channel(v)),
+			Key:      int(C.fluid_voice_get_key(v)),
+			Velocity: int(C.fluid_voice_get_velocity(v)),
+			Playing:  C.fluid_voice_is_playing(v) != 0,
+		})
+	}
+	return voices, nil
+}
+
+// Start plays a preset directly, without selecting it on the channel first. The voices are
+// tagged with voiceID, a handle chosen by the caller, which is later passed to Stop.
+// This bypasses the channel's program selection, but the voices still use the channel's
+// controllers (volume, pan, pitch bend, ...).
+func (s *Synth) Start(voiceID uint, channel uint8, sfontID, bank, program, key, vel int) error {
+	sfont := C.fluid_synth_get_sfont_by_id(s.ptr, C.int(sfontID))
+	if sfont == nil {
+		return fmt.Errorf("no soundfont with ID: %d", sfontID)
+	}
+	preset := C.fluid_sfont_get_preset(sfont, C.int(bank), C.int(program))
+	if preset == nil {
+		return fmt.Errorf("no preset in soundfont %d: bank=%d, program=%d", sfontID, bank, program)
+	}
+	if C.fluid_synth_start(s.ptr, C.uint(voiceID), preset, 0, C.int(channel), C.int(key), C.int(vel)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to start voice: id=%d, key=%d, velocity=%d", voiceID, key, vel)
+	}
+	return nil
+}
+
+// Stop releases all voices that were started with the given voiceID
+func (s *Synth) Stop(voiceID uint) error {
+	if C.fluid_synth_stop(s.ptr, C.uint(voiceID)) == C.FLUID_FAILED {
+		return fmt.Errorf("failed to stop voice: id=%d", voiceID)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package fluidsynth2
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetActiveVoices(t *testing.T) {
+	path := testSoundFont(t)
+	synth := newTestSynth(t)
+	if _, err := synth.SFLoad(path, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []uint8{60, 64} {
+		if err := synth.NoteOn(0, key, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	voices, err := synth.GetActiveVoices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a note can start several voices, e.g. for stereo samples
+	var keys []int
+	for _, v := range voices {
+		if !slices.Contains(keys, v.Key) {
+			keys = append(keys, v.Key)
+		}
+	}
+	slices.Sort(keys)
+	if !slices.Equal(keys, []int{60, 64}) {
+		t.Errorf("voices play keys %v, want [60 64]", keys)
+	}
+	if n := synth.GetActiveVoiceCount(); len(voices) != n {
+		t.Errorf("%d voices reported, GetActiveVoiceCount = %d", len(voices), n)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	path := testSoundFont(t)
+	synth := newTestSynth(t)
+	sfid, err := synth.SFLoad(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate, err := synth.GetSampleRate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := synth.GetActiveVoiceCount()
+	if err := synth.Start(1, 0, sfid, 0, 0, 60, 100); err != nil {
+		t.Fatal(err)
+	}
+	if n := synth.GetActiveVoiceCount(); n <= before {
+		t.Fatalf("%d active voices after Start, want more than %d", n, before)
+	}
+	if err := synth.Stop(1); err != nil {
+		t.Fatal(err)
+	}
+	// released voices stay active until their release phase is rendered
+	left := make([]int16, offlineBlockFrames)
+	right := make([]int16, offlineBlockFrames)
+	for rendered := 0; synth.GetActiveVoiceCount() > before && rendered < int(10*rate); rendered += offlineBlockFrames {
+		if err := synth.WriteS16(left, right, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := synth.GetActiveVoiceCount(); n != before {
+		t.Errorf("%d active voices after Stop, want %d", n, before)
+	}
+}
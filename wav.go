@@ -0,0 +1,121 @@
+package fluidsynth2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const wavHeaderSize = 44
+
+// WAVWriter writes 16-bit PCM WAV files from rendered samples without needing libsndfile.
+//
+// The RIFF header contains the size of the data, which isn't known until the writer is closed.
+// If the underlying writer is an io.WriteSeeker the header is written with placeholder sizes
+// and patched on Close. Otherwise the header is streamed with the sizes set to 0xFFFFFFFF,
+// which most players accept as "unknown length".
+type WAVWriter struct {
+	w          io.Writer
+	channels   int
+	dataBytes  int64
+	headerPos  int64
+	seeker     io.WriteSeeker
+	closed     bool
+	buf        []byte
+	sampleRate int
+}
+
+func NewWAVWriter(w io.Writer, sampleRate, channels int) (*WAVWriter, error) {
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %d", sampleRate)
+	}
+	if channels != 1 && channels != 2 {
+		return nil, fmt.Errorf("invalid channel count: %d", channels)
+	}
+	ww := &WAVWriter{w: w, sampleRate: sampleRate, channels: channels}
+	size := uint32(0xffffffff)
+	if s, ok := w.(io.WriteSeeker); ok {
+		pos, err := s.Seek(0, io.SeekCurrent)
+		if err == nil {
+			ww.seeker = s
+			ww.headerPos = pos
+			size = 0
+		}
+	}
+	if _, err := w.Write(ww.header(size, size)); err != nil {
+		return nil, err
+	}
+	return ww, nil
+}
+
+func (ww *WAVWriter) header(riffSize, dataSize uint32) []byte {
+	blockAlign := ww.channels * 2
+	h := make([]byte, wavHeaderSize)
+	copy(h[0:], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:], riffSize)
+	copy(h[8:], "WAVE")
+	copy(h[12:], "fmt ")
+	binary.LittleEndian.PutUint32(h[16:], 16)
+	binary.LittleEndian.PutUint16(h[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(h[22:], uint16(ww.channels))
+	binary.LittleEndian.PutUint32(h[24:], uint32(ww.sampleRate))
+	binary.LittleEndian.PutUint32(h[28:], uint32(ww.sampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(h[32:], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(h[34:], 16)
+	copy(h[36:], "data")
+	binary.LittleEndian.PutUint32(h[40:], dataSize)
+	return h
+}
+
+// WriteFrames writes one frame per sample of left and right, which must have the same length.
+// A mono writer stores the average of both channels.
+func (ww *WAVWriter) WriteFrames(left, right []int16) error {
+	if ww.closed {
+		return fmt.Errorf("WAV writer is closed")
+	}
+	if len(left) != len(right) {
+		return fmt.Errorf("channel lengths differ: left=%d, right=%d", len(left), len(right))
+	}
+	n := len(left) * ww.channels * 2
+	if cap(ww.buf) < n {
+		ww.buf = make([]byte, n)
+	}
+	b := ww.buf[:n]
+	for i := range left {
+		if ww.channels == 1 {
+			binary.LittleEndian.PutUint16(b[2*i:], uint16((int32(left[i])+int32(right[i]))/2))
+		} else {
+			binary.LittleEndian.PutUint16(b[4*i:], uint16(left[i]))
+			binary.LittleEndian.PutUint16(b[4*i+2:], uint16(right[i]))
+		}
+	}
+	written, err := ww.w.Write(b)
+	ww.dataBytes += int64(written)
+	return err
+}
+
+// Close finalizes the header if the underlying writer can seek. It doesn't close the underlying writer.
+func (ww *WAVWriter) Close() error {
+	if ww.closed {
+		return nil
+	}
+	ww.closed = true
+	if ww.seeker == nil {
+		return nil
+	}
+	if ww.dataBytes > 0xffffffff-(wavHeaderSize-8) {
+		return fmt.Errorf("WAV data too large: %d bytes", ww.dataBytes)
+	}
+	end, err := ww.seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := ww.seeker.Seek(ww.headerPos, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := ww.seeker.Write(ww.header(uint32(ww.dataBytes)+wavHeaderSize-8, uint32(ww.dataBytes))); err != nil {
+		return err
+	}
+	_, err = ww.seeker.Seek(end, io.SeekStart)
+	return err
+}
@@ -0,0 +1,108 @@
+package fluidsynth2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWAVWriter(t *testing.T) {
+	left := []int16{1, 2, 3, -32768}
+	right := []int16{-1, -2, 5, -32768}
+	tests := []struct {
+		name     string
+		channels int
+		seek     bool
+		samples  []int16
+	}{
+		{"stereo", 2, true, []int16{1, -1, 2, -2, 3, 5, -32768, -32768}},
+		{"mono", 1, true, []int16{0, 0, 4, -32768}},
+		{"stereo stream", 2, false, []int16{1, -1, 2, -2, 3, 5, -32768, -32768}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		var w io.Writer = &buf
+		var f *os.File
+		if tt.seek {
+			var err error
+			if f, err = os.Create(filepath.Join(t.TempDir(), "out.wav")); err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+		ww, err := NewWAVWriter(w, 22050, tt.channels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ww.WriteFrames(left, right); err != nil {
+			t.Fatal(err)
+		}
+		if err := ww.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := ww.WriteFrames(left, right); err == nil {
+			t.Errorf("%s: wrote to a closed writer", tt.name)
+		}
+		data := buf.Bytes()
+		if tt.seek {
+			if data, err = os.ReadFile(f.Name()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		dataSize := uint32(2 * len(tt.samples))
+		riffSize := dataSize + wavHeaderSize - 8
+		if !tt.seek {
+			dataSize, riffSize = 0xffffffff, 0xffffffff
+		}
+		le := binary.LittleEndian
+		fields := []struct {
+			name      string
+			got, want uint32
+		}{
+			{"RIFF size", le.Uint32(data[4:]), riffSize},
+			{"format", uint32(le.Uint16(data[20:])), 1},
+			{"channels", uint32(le.Uint16(data[22:])), uint32(tt.channels)},
+			{"sample rate", le.Uint32(data[24:]), 22050},
+			{"byte rate", le.Uint32(data[28:]), uint32(22050 * 2 * tt.channels)},
+			{"block align", uint32(le.Uint16(data[32:])), uint32(2 * tt.channels)},
+			{"bits per sample", uint32(le.Uint16(data[34:])), 16},
+			{"data size", le.Uint32(data[40:]), dataSize},
+			{"file size", uint32(len(data)), wavHeaderSize + uint32(2*len(tt.samples))},
+		}
+		for _, field := range fields {
+			if field.got != field.want {
+				t.Errorf("%s: %s = %d, want %d", tt.name, field.name, field.got, field.want)
+			}
+		}
+		if string(data[0:4]) != "RIFF" || string(data[8:16]) != "WAVEfmt " || string(data[36:40]) != "data" {
+			t.Errorf("%s: bad chunk IDs in header %q", tt.name, data[:wavHeaderSize])
+		}
+		for i, want := range tt.samples {
+			if got := int16(le.Uint16(data[wavHeaderSize+2*i:])); got != want {
+				t.Errorf("%s: sample %d = %d, want %d", tt.name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestWAVWriterInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := NewWAVWriter(&buf, 0, 2); err == nil {
+		t.Error("accepted a sample rate of 0")
+	}
+	if _, err := NewWAVWriter(&buf, 44100, 3); err == nil {
+		t.Error("accepted 3 channels")
+	}
+	ww, err := NewWAVWriter(&buf, 44100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ww.WriteFrames([]int16{1, 2}, []int16{1}); err == nil {
+		t.Error("accepted channels of different lengths")
+	}
+}